@@ -17,9 +17,9 @@ require (
 	github.com/pasdam/files-index v0.0.0-20251027145827-bf1f76a08090
 	github.com/pasdam/go-io-utilx v0.0.0-20251027152920-7448902636f4
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/stretchr/objx v0.5.2 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 retract v2.0.0
@@ -0,0 +1,157 @@
+package collectors
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// defaultArchiveModTime is the modification time of the archive entries if
+// not specified, the earliest time supported by the zip format
+var defaultArchiveModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// errArchiveClosed is returned when an archive collector is used after the
+// archive has been written
+var errArchiveClosed = errors.New("the archive has already been written, the collector can be used for a single run")
+
+type ArchiveCollectorOptions struct {
+	Prefix  string    // Folder that contains all the files in the archive; defaults to the root
+	ModTime time.Time // Modification time of the entries; defaults to 1980-01-01 00:00:00 UTC, for reproducible archives
+}
+
+// archiveEntry is a file to add to an archive
+type archiveEntry struct {
+	path    string
+	mode    fs.FileMode
+	content []byte
+}
+
+// archiveWriter writes the entries to an archive format
+type archiveWriter interface {
+	writeEntry(entry *archiveEntry, modTime time.Time) error
+	close() error
+}
+
+// archiveCollector collects the templates into an archive, written when the
+// pipeline completes: the entries are sorted by path, and have the same
+// modification time, so that the same templates always produce the same
+// archive. The collector can be used for a single run: once the archive has
+// been written, it returns an error instead of collecting other templates.
+type archiveCollector struct {
+	baseCollector
+
+	opts    ArchiveCollectorOptions
+	writer  archiveWriter
+	entries map[string]*archiveEntry
+	closed  bool
+}
+
+func newArchiveCollector(writer archiveWriter, opts ArchiveCollectorOptions, nextCollector pipeline.Collector) *archiveCollector {
+	if opts.ModTime.IsZero() {
+		opts.ModTime = defaultArchiveModTime
+	}
+	return &archiveCollector{
+		baseCollector: baseCollector{
+			next: nextCollector,
+		},
+		opts:    opts,
+		writer:  writer,
+		entries: make(map[string]*archiveEntry),
+	}
+}
+
+func (p *archiveCollector) Collect(args *pipeline.Template) error {
+	return p.CollectStream(args, pipeline.RenderReader(args.Reader))
+}
+
+// CollectStream buffers the content of the template until the pipeline
+// completes, and forwards it to the next collector, if any. A template with
+// the same path of a previous one replaces it. It returns an error if the
+// archive has already been written.
+func (p *archiveCollector) CollectStream(args *pipeline.Template, render pipeline.RenderFunc) error {
+	if p.closed {
+		return errArchiveClosed
+	}
+
+	var content bytes.Buffer
+	err := render(&content)
+	if err != nil {
+		return err
+	}
+
+	mode := args.Mode.Perm()
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	entryPath := path.Join(p.opts.Prefix, args.Path)
+	p.entries[entryPath] = &archiveEntry{
+		path:    entryPath,
+		mode:    mode,
+		content: content.Bytes(),
+	}
+
+	if p.next == nil {
+		return nil
+	}
+	tpl := *args
+	tpl.Reader = nil
+	return pipeline.CollectStream(p.next, &tpl, pipeline.RenderReader(bytes.NewReader(content.Bytes())))
+}
+
+// Results returns the files added to the archive, sorted by path, followed by
+// the results of the next collector
+func (p *archiveCollector) Results() []pipeline.FileResult {
+	results := make([]pipeline.FileResult, 0, len(p.entries))
+	for _, entry := range p.sortedEntries() {
+		results = append(results, pipeline.FileResult{
+			Path:   entry.path,
+			Status: pipeline.FileWritten,
+			Bytes:  int64(len(entry.content)),
+		})
+	}
+	return append(results, p.nextResults()...)
+}
+
+// OnPipelineCompleted writes the entries and finalizes the archive, then
+// notifies the next collector. It returns an error if the archive has already
+// been written.
+func (p *archiveCollector) OnPipelineCompleted() error {
+	err := p.writeArchive()
+	if err != nil {
+		return err
+	}
+
+	if p.next == nil {
+		return nil
+	}
+	return p.next.OnPipelineCompleted()
+}
+
+func (p *archiveCollector) writeArchive() error {
+	if p.closed {
+		return errArchiveClosed
+	}
+	p.closed = true
+
+	for _, entry := range p.sortedEntries() {
+		err := p.writer.writeEntry(entry, p.opts.ModTime)
+		if err != nil {
+			return err
+		}
+	}
+	return p.writer.close()
+}
+
+func (p *archiveCollector) sortedEntries() []*archiveEntry {
+	entries := make([]*archiveEntry, 0, len(p.entries))
+	for _, entry := range p.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].path < entries[j].path })
+	return entries
+}
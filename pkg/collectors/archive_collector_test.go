@@ -0,0 +1,203 @@
+package collectors
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type archiveFile struct {
+	path    string
+	mode    fs.FileMode
+	modTime time.Time
+	content string
+}
+
+func readZip(t *testing.T, content []byte) []archiveFile {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+
+	files := make([]archiveFile, 0)
+	for _, file := range reader.File {
+		fileReader, err := file.Open()
+		assert.NoError(t, err)
+		fileContent, err := io.ReadAll(fileReader)
+		assert.NoError(t, err)
+		files = append(files, archiveFile{
+			path:    file.Name,
+			mode:    file.Mode(),
+			modTime: file.Modified.UTC(),
+			content: string(fileContent),
+		})
+	}
+	return files
+}
+
+func readTarGz(t *testing.T, content []byte) []archiveFile {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+	assert.NoError(t, err)
+	reader := tar.NewReader(gzipReader)
+
+	files := make([]archiveFile, 0)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		fileContent, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		files = append(files, archiveFile{
+			path:    header.Name,
+			mode:    header.FileInfo().Mode(),
+			modTime: header.ModTime.UTC(),
+			content: string(fileContent),
+		})
+	}
+	return files
+}
+
+func collectArchiveTemplates(t *testing.T, p pipeline.Collector, templates []*pipeline.Template, contents []string) {
+	for i, tpl := range templates {
+		tpl.Reader = io.NopCloser(strings.NewReader(contents[i]))
+		assert.NoError(t, p.Collect(tpl))
+	}
+	assert.NoError(t, p.OnPipelineCompleted())
+}
+
+func TestArchiveCollectors(t *testing.T) {
+	modTime := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		newArchive  func(w io.Writer, opts ArchiveCollectorOptions) pipeline.Collector
+		readArchive func(t *testing.T, content []byte) []archiveFile
+		opts        ArchiveCollectorOptions
+		want        []archiveFile
+	}{
+		{
+			name: "Should write zip archive with defaults",
+			newArchive: func(w io.Writer, opts ArchiveCollectorOptions) pipeline.Collector {
+				return NewZipCollector(w, opts, nil)
+			},
+			readArchive: readZip,
+			want: []archiveFile{
+				{path: "a/script.sh", mode: 0755, modTime: defaultArchiveModTime, content: "some-script"},
+				{path: "b.txt", mode: 0644, modTime: defaultArchiveModTime, content: "some-new-content"},
+				{path: "c.txt", mode: 0600, modTime: defaultArchiveModTime, content: "some-secret"},
+			},
+		},
+		{
+			name: "Should write zip archive with options",
+			newArchive: func(w io.Writer, opts ArchiveCollectorOptions) pipeline.Collector {
+				return NewZipCollector(w, opts, nil)
+			},
+			readArchive: readZip,
+			opts:        ArchiveCollectorOptions{Prefix: "project", ModTime: modTime},
+			want: []archiveFile{
+				{path: "project/a/script.sh", mode: 0755, modTime: modTime, content: "some-script"},
+				{path: "project/b.txt", mode: 0644, modTime: modTime, content: "some-new-content"},
+				{path: "project/c.txt", mode: 0600, modTime: modTime, content: "some-secret"},
+			},
+		},
+		{
+			name: "Should write tar.gz archive with defaults",
+			newArchive: func(w io.Writer, opts ArchiveCollectorOptions) pipeline.Collector {
+				return NewTarGzCollector(w, opts, nil)
+			},
+			readArchive: readTarGz,
+			want: []archiveFile{
+				{path: "a/script.sh", mode: 0755, modTime: defaultArchiveModTime, content: "some-script"},
+				{path: "b.txt", mode: 0644, modTime: defaultArchiveModTime, content: "some-new-content"},
+				{path: "c.txt", mode: 0600, modTime: defaultArchiveModTime, content: "some-secret"},
+			},
+		},
+		{
+			name: "Should write tar.gz archive with options",
+			newArchive: func(w io.Writer, opts ArchiveCollectorOptions) pipeline.Collector {
+				return NewTarGzCollector(w, opts, nil)
+			},
+			readArchive: readTarGz,
+			opts:        ArchiveCollectorOptions{Prefix: "project", ModTime: modTime},
+			want: []archiveFile{
+				{path: "project/a/script.sh", mode: 0755, modTime: modTime, content: "some-script"},
+				{path: "project/b.txt", mode: 0644, modTime: modTime, content: "some-new-content"},
+				{path: "project/c.txt", mode: 0600, modTime: modTime, content: "some-secret"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var first, second bytes.Buffer
+
+			collectArchiveTemplates(t, tt.newArchive(&first, tt.opts),
+				[]*pipeline.Template{{Path: "c.txt", Mode: 0600}, {Path: "b.txt"}, {Path: "a/script.sh", Mode: 0755}, {Path: "b.txt"}},
+				[]string{"some-secret", "some-content", "some-script", "some-new-content"},
+			)
+			collectArchiveTemplates(t, tt.newArchive(&second, tt.opts),
+				[]*pipeline.Template{{Path: "a/script.sh", Mode: 0755}, {Path: "b.txt"}, {Path: "c.txt", Mode: 0600}},
+				[]string{"some-script", "some-new-content", "some-secret"},
+			)
+
+			assert.Equal(t, tt.want, tt.readArchive(t, first.Bytes()))
+			assert.Equal(t, first.Bytes(), second.Bytes())
+		})
+	}
+}
+
+func Test_archiveCollector_Collect(t *testing.T) {
+	next := &readingCollector{}
+	next.On("OnPipelineCompleted").Return(errors.New("some-error"))
+	var archive bytes.Buffer
+	p := NewZipCollector(&archive, ArchiveCollectorOptions{}, next).(*archiveCollector)
+
+	assert.NoError(t, p.Collect(&pipeline.Template{Path: "file.txt", Reader: io.NopCloser(strings.NewReader("some-content"))}))
+	err := p.CollectStream(&pipeline.Template{Path: "other.txt"}, func(w io.Writer) error { return errors.New("some-render-error") })
+	assert.Equal(t, errors.New("some-render-error"), err)
+
+	assert.Equal(t, []string{"some-content"}, next.contents)
+	assert.Equal(t, errors.New("some-error"), p.OnPipelineCompleted())
+	assert.Equal(t, []archiveFile{{path: "file.txt", mode: 0644, modTime: defaultArchiveModTime, content: "some-content"}}, readZip(t, archive.Bytes()))
+}
+
+func Test_archiveCollector_ShouldReturnErrorOnceTheArchiveIsWritten(t *testing.T) {
+	var archive bytes.Buffer
+	p := NewFilterCollector(filters.NewNoOpFilter(), NewZipCollector(&archive, ArchiveCollectorOptions{}, nil)).(pipeline.ReusableCollector)
+	first := p.NewRun()
+	assert.NoError(t, first.Collect(&pipeline.Template{Path: "file.txt", Reader: io.NopCloser(strings.NewReader("some-content"))}))
+	assert.NoError(t, first.OnPipelineCompleted())
+	written := append([]byte{}, archive.Bytes()...)
+
+	second := p.NewRun()
+
+	assert.Equal(t, errArchiveClosed, second.Collect(&pipeline.Template{Path: "other.txt", Reader: io.NopCloser(strings.NewReader("other-content"))}))
+	assert.Equal(t, errArchiveClosed, second.OnPipelineCompleted())
+	assert.Equal(t, written, archive.Bytes())
+	assert.Equal(t, []archiveFile{{path: "file.txt", mode: 0644, modTime: defaultArchiveModTime, content: "some-content"}}, readZip(t, archive.Bytes()))
+}
+
+func Test_archiveCollector_Results(t *testing.T) {
+	next := &mockReportingCollector{results: []pipeline.FileResult{{Path: "some-path", Status: pipeline.FileSkipped}}}
+	next.On("Collect", mock.Anything).Return(nil)
+	p := NewTarGzCollector(io.Discard, ArchiveCollectorOptions{Prefix: "project"}, next).(*archiveCollector)
+
+	assert.NoError(t, p.Collect(&pipeline.Template{Path: "b.txt", Reader: io.NopCloser(strings.NewReader("some-content"))}))
+	assert.NoError(t, p.Collect(&pipeline.Template{Path: "a.txt", Reader: io.NopCloser(strings.NewReader("abc"))}))
+
+	assert.Equal(t, []pipeline.FileResult{
+		{Path: "project/a.txt", Status: pipeline.FileWritten, Bytes: 3},
+		{Path: "project/b.txt", Status: pipeline.FileWritten, Bytes: 12},
+		{Path: "some-path", Status: pipeline.FileSkipped},
+	}, p.Results())
+}
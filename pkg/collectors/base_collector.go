@@ -7,3 +7,25 @@ import (
 type baseCollector struct {
 	next pipeline.Collector
 }
+
+// nextRun returns the next collector to use in a new run
+func (c *baseCollector) nextRun() pipeline.Collector {
+	return pipeline.NewCollectorRun(c.next)
+}
+
+// nextResults returns the results reported by the next collector, if any
+func (c *baseCollector) nextResults() []pipeline.FileResult {
+	if c.next == nil {
+		return nil
+	}
+	return pipeline.CollectorResults(c.next)
+}
+
+// sourceOf returns the path of the main template that generated the
+// specified one
+func sourceOf(args *pipeline.Template) string {
+	if len(args.Source) > 0 {
+		return args.Source
+	}
+	return args.Path
+}
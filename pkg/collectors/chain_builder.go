@@ -0,0 +1,117 @@
+package collectors
+
+import (
+	"io"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// ChainLink creates a collector that forwards the templates to next
+type ChainLink func(next pipeline.Collector) pipeline.Collector
+
+// ChainBuilder builds a chain of collectors in the order they are added,
+// instead of nesting their constructors: i.e.
+//
+//	NewChainBuilder().Split().Filter(filter).WriteFiles(outDir).Build()
+//
+// is equivalent to
+//
+//	NewSplitterCollector(NewFilterCollector(filter, NewFileWriterCollector(outDir, nil)))
+type ChainBuilder struct {
+	links []ChainLink
+}
+
+// NewChainBuilder creates an empty chain builder
+func NewChainBuilder() *ChainBuilder {
+	return &ChainBuilder{}
+}
+
+// Then adds a collector created by link, i.e. a custom collector
+func (b *ChainBuilder) Then(link ChainLink) *ChainBuilder {
+	b.links = append(b.links, link)
+	return b
+}
+
+// Filter adds a filter collector, see NewFilterCollector
+func (b *ChainBuilder) Filter(filter filters.Filter) *ChainBuilder {
+	return b.Then(func(next pipeline.Collector) pipeline.Collector {
+		return NewFilterCollector(filter, next)
+	})
+}
+
+// Split adds a splitter collector, see NewSplitterCollector
+func (b *ChainBuilder) Split() *ChainBuilder {
+	return b.SplitWithOpts(SplitterCollectorOptions{})
+}
+
+// SplitWithOpts adds a splitter collector, see NewSplitterCollectorWithOpts
+func (b *ChainBuilder) SplitWithOpts(opts SplitterCollectorOptions) *ChainBuilder {
+	return b.Then(func(next pipeline.Collector) pipeline.Collector {
+		return NewSplitterCollectorWithOpts(opts, next)
+	})
+}
+
+// Transform adds a transformer collector, see NewTransformerCollector
+func (b *ChainBuilder) Transform(filter filters.Filter, transformer Transformer) *ChainBuilder {
+	return b.Then(func(next pipeline.Collector) pipeline.Collector {
+		return NewTransformerCollector(filter, transformer, next)
+	})
+}
+
+// ValidateGo adds a Go validator collector, see NewGoValidatorCollector
+func (b *ChainBuilder) ValidateGo(opts GoValidatorCollectorOptions) *ChainBuilder {
+	return b.Then(func(next pipeline.Collector) pipeline.Collector {
+		return NewGoValidatorCollector(opts, next)
+	})
+}
+
+// WriteFiles adds a file writer collector, see NewFileWriterCollector
+func (b *ChainBuilder) WriteFiles(outDir string) *ChainBuilder {
+	return b.Then(func(next pipeline.Collector) pipeline.Collector {
+		return NewFileWriterCollector(outDir, next)
+	})
+}
+
+// WriteFilesWithOpts adds a file writer collector, see
+// NewFileWriterCollectorWithOpts
+func (b *ChainBuilder) WriteFilesWithOpts(opts FileWriterCollectorOptions) *ChainBuilder {
+	return b.Then(func(next pipeline.Collector) pipeline.Collector {
+		return NewFileWriterCollectorWithOpts(opts, next)
+	})
+}
+
+// Zip adds a zip archive collector, see NewZipCollector
+func (b *ChainBuilder) Zip(w io.Writer, opts ArchiveCollectorOptions) *ChainBuilder {
+	return b.Then(func(next pipeline.Collector) pipeline.Collector {
+		return NewZipCollector(w, opts, next)
+	})
+}
+
+// TarGz adds a tar.gz archive collector, see NewTarGzCollector
+func (b *ChainBuilder) TarGz(w io.Writer, opts ArchiveCollectorOptions) *ChainBuilder {
+	return b.Then(func(next pipeline.Collector) pipeline.Collector {
+		return NewTarGzCollector(w, opts, next)
+	})
+}
+
+// Tee adds a tee collector that delivers the templates to the branches, and to
+// the rest of the chain, if any, as the last branch; see NewTeeCollector
+func (b *ChainBuilder) Tee(branches ...pipeline.Collector) *ChainBuilder {
+	return b.Then(func(next pipeline.Collector) pipeline.Collector {
+		if next == nil {
+			return NewTeeCollector(branches...)
+		}
+		return NewTeeCollector(append(append([]pipeline.Collector{}, branches...), next)...)
+	})
+}
+
+// Build creates the chain, it returns nil if no collector has been added. The
+// last collector of the chain has no next collector.
+func (b *ChainBuilder) Build() pipeline.Collector {
+	var collector pipeline.Collector
+	for i := len(b.links) - 1; i >= 0; i-- {
+		collector = b.links[i](collector)
+	}
+	return collector
+}
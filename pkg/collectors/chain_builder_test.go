@@ -0,0 +1,83 @@
+package collectors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainBuilder_Build(t *testing.T) {
+	filter := filters.NewNoOpFilter()
+	custom := &mockCollector{}
+	branch := &mockCollector{}
+
+	tests := []struct {
+		name  string
+		build func(b *ChainBuilder) *ChainBuilder
+		want  pipeline.Collector
+	}{
+		{
+			name:  "Should return nil for an empty chain",
+			build: func(b *ChainBuilder) *ChainBuilder { return b },
+			want:  nil,
+		},
+		{
+			name: "Should chain collectors in the order they are added",
+			build: func(b *ChainBuilder) *ChainBuilder {
+				return b.Split().Filter(filter).WriteFiles("some-dir")
+			},
+			want: NewSplitterCollector(NewFilterCollector(filter, NewFileWriterCollector("some-dir", nil))),
+		},
+		{
+			name: "Should chain collectors with options and custom links",
+			build: func(b *ChainBuilder) *ChainBuilder {
+				return b.
+					SplitWithOpts(SplitterCollectorOptions{}).
+					WriteFilesWithOpts(FileWriterCollectorOptions{OutDir: "some-dir", SkipUnchanged: true}).
+					Then(func(next pipeline.Collector) pipeline.Collector {
+						assert.Nil(t, next)
+						return custom
+					})
+			},
+			want: NewSplitterCollectorWithOpts(SplitterCollectorOptions{}, NewFileWriterCollectorWithOpts(FileWriterCollectorOptions{OutDir: "some-dir", SkipUnchanged: true}, custom)),
+		},
+		{
+			name: "Should chain transformer and validator",
+			build: func(b *ChainBuilder) *ChainBuilder {
+				return b.Transform(filter, nil).ValidateGo(GoValidatorCollectorOptions{TypeCheck: true}).Then(func(pipeline.Collector) pipeline.Collector { return custom })
+			},
+			want: NewTransformerCollector(filter, nil, NewGoValidatorCollector(GoValidatorCollectorOptions{TypeCheck: true}, custom)),
+		},
+		{
+			name: "Should chain archive collectors",
+			build: func(b *ChainBuilder) *ChainBuilder {
+				return b.Zip(io.Discard, ArchiveCollectorOptions{Prefix: "zip"}).TarGz(io.Discard, ArchiveCollectorOptions{Prefix: "tar"})
+			},
+			want: NewZipCollector(io.Discard, ArchiveCollectorOptions{Prefix: "zip"}, NewTarGzCollector(io.Discard, ArchiveCollectorOptions{Prefix: "tar"}, nil)),
+		},
+		{
+			name: "Should add rest of the chain as last branch of tee",
+			build: func(b *ChainBuilder) *ChainBuilder {
+				return b.Tee(branch).WriteFiles("some-dir")
+			},
+			want: NewTeeCollector(branch, NewFileWriterCollector("some-dir", nil)),
+		},
+		{
+			name: "Should create tee with the branches only at the end of the chain",
+			build: func(b *ChainBuilder) *ChainBuilder {
+				return b.Filter(filter).Tee(branch, custom)
+			},
+			want: NewFilterCollector(filter, NewTeeCollector(branch, custom)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.build(NewChainBuilder()).Build()
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,20 +1,26 @@
 package collectors
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
-	"github.com/pasdam/go-io-utilx/pkg/ioutilx"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 )
 
+const defaultFileMode fs.FileMode = 0644
+
 type FileWriterCollectorOptions struct {
 	OutDir           string
 	SkipUnchanged    bool
-	CleanupUntracked bool // Flag to control whether to remove untracked files; defaults to false to maintain current behavior
+	CleanupUntracked bool         // Flag to control whether to remove untracked files; defaults to false to maintain current behavior
+	Logger           *slog.Logger // Logger used by the collector; defaults to slog.Default()
 }
 
 type fileWriterCollector struct {
@@ -22,6 +28,7 @@ type fileWriterCollector struct {
 
 	opts           FileWriterCollectorOptions
 	generatedFiles map[string]bool // Track files generated during pipeline execution
+	results        []pipeline.FileResult
 }
 
 func NewFileWriterCollector(outDir string, nextCollector pipeline.Collector) pipeline.Collector {
@@ -46,39 +53,163 @@ func NewFileWriterCollectorWithOpts(opts FileWriterCollectorOptions, nextCollect
 	}
 }
 
+// NewRun returns a new collector with the same options, that tracks the files
+// generated in a single run.
+func (p *fileWriterCollector) NewRun() pipeline.Collector {
+	return NewFileWriterCollectorWithOpts(p.opts, p.nextRun())
+}
+
 func (p *fileWriterCollector) Collect(args *pipeline.Template) error {
+	return p.CollectStream(args, pipeline.RenderReader(args.Reader))
+}
+
+// CollectStream renders the template directly into the output file, the next
+// collector (if any) reads the content back from it.
+func (p *fileWriterCollector) CollectStream(args *pipeline.Template, render pipeline.RenderFunc) error {
 	outPath := filepath.Join(p.opts.OutDir, args.Path)
 
-	contentBytes, err := io.ReadAll(args.Reader)
+	_, span := tracing.Start(args.Context(), "collectors.fileWriter", tracing.String("path", args.Path))
+	result, err := p.writeFile(outPath, args.Mode.Perm(), render)
+	span.SetAttributes(tracing.String("status", string(result.Status)), tracing.Int64("bytes", result.Bytes))
+	span.RecordError(err)
+	span.End()
 	if err != nil {
 		return err
 	}
 
-	writeFile := true
-	if p.opts.SkipUnchanged {
-		existingContent, err := os.ReadFile(outPath)
-		if err == nil && bytes.Equal(existingContent, contentBytes) {
-			writeFile = false
-		}
+	p.generatedFiles[outPath] = true
+	result.Path = args.Path
+	p.results = append(p.results, result)
+
+	if p.next == nil {
+		return nil
 	}
 
-	if writeFile {
-		err = ioutilx.ReaderToFile(bytes.NewReader(contentBytes), outPath)
+	forwarded := (&pipeline.Template{Path: args.Path, Source: args.Source, Origin: args.Origin, Mode: args.Mode}).WithContext(args.Context())
+	return pipeline.CollectStream(p.next, forwarded, func(w io.Writer) error {
+		file, err := os.Open(outPath)
 		if err != nil {
 			return err
 		}
+		defer file.Close()
+
+		_, err = io.Copy(w, file)
+		return err
+	})
+}
+
+// writeFile renders the content into a temporary file in the output folder,
+// that replaces the output file, unless SkipUnchanged is set and they have the
+// same content and mode. This ensures that the output file is not left
+// partially written if the rendering fails. The file gets the specified mode,
+// if not zero, otherwise the one of the existing file, or 0644 for new files.
+func (p *fileWriterCollector) writeFile(outPath string, mode fs.FileMode, render pipeline.RenderFunc) (pipeline.FileResult, error) {
+	result := pipeline.FileResult{}
+
+	err := os.MkdirAll(filepath.Dir(outPath), os.ModePerm)
+	if err != nil {
+		return result, err
 	}
 
-	p.generatedFiles[outPath] = true
+	tmpFile, err := os.CreateTemp(filepath.Dir(outPath), "."+filepath.Base(outPath)+".*")
+	if err != nil {
+		return result, err
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath) // no-op if the file has been renamed
 
-	if p.next == nil {
-		return nil
+	err = render(tmpFile)
+	closeErr := tmpFile.Close()
+	if err != nil {
+		return result, err
+	}
+	if closeErr != nil {
+		return result, closeErr
 	}
 
-	return p.next.Collect(&pipeline.Template{
-		Path:   args.Path,
-		Reader: io.NopCloser(bytes.NewReader(contentBytes)),
-	})
+	tmpInfo, err := os.Stat(tmpPath)
+	if err != nil {
+		return result, err
+	}
+	result.Bytes = tmpInfo.Size()
+
+	if info, err := os.Stat(outPath); err == nil {
+		if mode == 0 {
+			mode = info.Mode().Perm()
+		}
+		if p.opts.SkipUnchanged && info.Mode().Perm() == mode {
+			unchanged, err := sameContent(tmpPath, outPath)
+			if err != nil {
+				return result, err
+			}
+			if unchanged {
+				result.Status = pipeline.FileUnchanged
+				return result, nil
+			}
+		}
+	}
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+
+	err = os.Chmod(tmpPath, mode)
+	if err != nil {
+		return result, err
+	}
+	result.Status = pipeline.FileWritten
+	return result, os.Rename(tmpPath, outPath)
+}
+
+// sameContent returns true if the files at the specified paths have the same
+// content, reading them in chunks to avoid loading them in memory.
+func sameContent(path1, path2 string) (bool, error) {
+	info1, err := os.Stat(path1)
+	if err != nil {
+		return false, err
+	}
+	info2, err := os.Stat(path2)
+	if err != nil {
+		return false, err
+	}
+	if info1.Size() != info2.Size() {
+		return false, nil
+	}
+
+	file1, err := os.Open(path1)
+	if err != nil {
+		return false, err
+	}
+	defer file1.Close()
+	file2, err := os.Open(path2)
+	if err != nil {
+		return false, err
+	}
+	defer file2.Close()
+
+	reader1 := bufio.NewReader(file1)
+	reader2 := bufio.NewReader(file2)
+	chunk1 := make([]byte, 32*1024)
+	chunk2 := make([]byte, 32*1024)
+	for {
+		n1, err1 := io.ReadFull(reader1, chunk1)
+		n2, err2 := io.ReadFull(reader2, chunk2)
+		if !bytes.Equal(chunk1[:n1], chunk2[:n2]) {
+			return false, nil
+		}
+		if isEndOfFile(err1) || isEndOfFile(err2) {
+			return isEndOfFile(err1) && isEndOfFile(err2), nil
+		}
+		if err1 != nil {
+			return false, err1
+		}
+		if err2 != nil {
+			return false, err2
+		}
+	}
+}
+
+func isEndOfFile(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
 }
 
 func (p *fileWriterCollector) OnPipelineCompleted() error {
@@ -96,6 +227,12 @@ func (p *fileWriterCollector) OnPipelineCompleted() error {
 	return p.next.OnPipelineCompleted()
 }
 
+// Results returns the files written, unchanged and removed in the current run,
+// followed by the results of the next collector
+func (p *fileWriterCollector) Results() []pipeline.FileResult {
+	return append(append([]pipeline.FileResult{}, p.results...), p.nextResults()...)
+}
+
 // cleanupUntrackedFiles removes files from the output directory that were not generated during the pipeline execution
 func (p *fileWriterCollector) cleanupUntrackedFiles() error {
 	// Walk through the output directory
@@ -112,12 +249,22 @@ func (p *fileWriterCollector) cleanupUntrackedFiles() error {
 
 		// If the file was not generated during pipeline execution, remove it
 		if !p.generatedFiles[path] {
-			slog.Info("Removing untracked file", slog.String("path", path))
+			logger := loggerOrDefault(p.opts.Logger)
+			logger.Info("Removing untracked file", slog.String("path", path))
 			err := os.Remove(path)
 			if err != nil {
 				// Log the error but continue processing other files
+				logger.Warn("Unable to remove untracked file", slog.String("path", path), slog.Any("error", err))
 				return nil
 			}
+			relativePath, err := filepath.Rel(p.opts.OutDir, path)
+			if err != nil {
+				relativePath = path
+			}
+			p.results = append(p.results, pipeline.FileResult{
+				Path:   relativePath,
+				Status: pipeline.FileRemoved,
+			})
 		}
 
 		return nil
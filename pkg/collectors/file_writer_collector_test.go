@@ -2,8 +2,10 @@ package collectors
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -11,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 	"github.com/pasdam/go-io-utilx/pkg/ioutilx"
 	"github.com/pasdam/go-utils/pkg/assertutils"
 	"github.com/pasdam/go-utils/pkg/filetestutils"
@@ -421,3 +424,271 @@ func Test_fileWriterCollector_OnPipelineCompleted(t *testing.T) {
 		})
 	}
 }
+
+func Test_fileWriterCollector_CollectStream(t *testing.T) {
+	tests := []struct {
+		name            string
+		existingContent string
+		content         string
+		renderErr       error
+		withNext        bool
+		wantContent     string
+		wantErr         error
+	}{
+		{
+			name:        "Should write rendered content to file",
+			content:     "some-content",
+			wantContent: "some-content",
+		},
+		{
+			name:            "Should overwrite existing file",
+			existingContent: "some-old-content",
+			content:         "some-content",
+			wantContent:     "some-content",
+		},
+		{
+			name:        "Should stream content of the written file to the next collector",
+			content:     "some-content",
+			withNext:    true,
+			wantContent: "some-content",
+		},
+		{
+			name:            "Should not modify existing file if rendering fails",
+			existingContent: "some-old-content",
+			content:         "some-partial-content",
+			renderErr:       errors.New("some-render-error"),
+			wantContent:     "some-old-content",
+			wantErr:         errors.New("some-render-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := filetestutils.TempDir(t)
+			outPath := filepath.Join(outDir, "some-dir", "some-file")
+			if len(tt.existingContent) > 0 {
+				err := ioutilx.ReaderToFile(strings.NewReader(tt.existingContent), outPath)
+				assert.NoError(t, err)
+			}
+			next := &mockStreamingCollector{}
+			var nextCollector pipeline.Collector
+			if tt.withNext {
+				nextCollector = next
+			}
+			p := NewFileWriterCollector(outDir, nextCollector).(*fileWriterCollector)
+
+			err := p.CollectStream(&pipeline.Template{Path: filepath.Join("some-dir", "some-file")}, func(w io.Writer) error {
+				_, err := io.WriteString(w, tt.content)
+				if err != nil {
+					return err
+				}
+				return tt.renderErr
+			})
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			filetestutils.FileExistsWithContent(t, outPath, tt.wantContent)
+			entries, err := os.ReadDir(filepath.Dir(outPath))
+			assert.NoError(t, err)
+			assert.Len(t, entries, 1, "temporary files should be removed")
+			if tt.withNext {
+				assert.Equal(t, []string{filepath.Join("some-dir", "some-file")}, next.paths)
+				assert.Equal(t, []string{tt.content}, next.contents)
+			}
+		})
+	}
+}
+
+func Test_fileWriterCollector_CollectStream_mode(t *testing.T) {
+	tests := []struct {
+		name          string
+		existingMode  os.FileMode
+		mode          os.FileMode
+		skipUnchanged bool
+		wantMode      os.FileMode
+		wantStatus    pipeline.FileStatus
+	}{
+		{
+			name:       "Should create file with the mode of the template",
+			mode:       0755,
+			wantMode:   0755,
+			wantStatus: pipeline.FileWritten,
+		},
+		{
+			name:       "Should create file with the default mode if the template has none",
+			wantMode:   0644,
+			wantStatus: pipeline.FileWritten,
+		},
+		{
+			name:         "Should replace the mode of an existing file with the one of the template",
+			existingMode: 0600,
+			mode:         0755,
+			wantMode:     0755,
+			wantStatus:   pipeline.FileWritten,
+		},
+		{
+			name:         "Should keep the mode of an existing file if the template has none",
+			existingMode: 0600,
+			wantMode:     0600,
+			wantStatus:   pipeline.FileWritten,
+		},
+		{
+			name:          "Should update the mode of an unchanged file",
+			existingMode:  0644,
+			mode:          0755,
+			skipUnchanged: true,
+			wantMode:      0755,
+			wantStatus:    pipeline.FileWritten,
+		},
+		{
+			name:          "Should skip a file with the same content and mode",
+			existingMode:  0755,
+			mode:          0755,
+			skipUnchanged: true,
+			wantMode:      0755,
+			wantStatus:    pipeline.FileUnchanged,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := filetestutils.TempDir(t)
+			outPath := filepath.Join(outDir, "run.sh")
+			if tt.existingMode != 0 {
+				assert.NoError(t, os.WriteFile(outPath, []byte("some-content"), tt.existingMode))
+				assert.NoError(t, os.Chmod(outPath, tt.existingMode))
+			}
+			p := NewFileWriterCollectorWithOpts(FileWriterCollectorOptions{OutDir: outDir, SkipUnchanged: tt.skipUnchanged}, nil).(*fileWriterCollector)
+
+			err := p.CollectStream(&pipeline.Template{Path: "run.sh", Mode: tt.mode}, pipeline.RenderReader(strings.NewReader("some-content")))
+
+			assert.NoError(t, err)
+			info, err := os.Stat(outPath)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMode, info.Mode().Perm())
+			assert.Equal(t, tt.wantStatus, p.Results()[0].Status)
+		})
+	}
+}
+
+func Test_sameContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content1 string
+		content2 string
+		want     bool
+	}{
+		{
+			name:     "Should return true for files with same content",
+			content1: strings.Repeat("some-content", 10000),
+			content2: strings.Repeat("some-content", 10000),
+			want:     true,
+		},
+		{
+			name:     "Should return false for files with different size",
+			content1: "some-content",
+			content2: "some-longer-content",
+			want:     false,
+		},
+		{
+			name:     "Should return false for files with same size and different content",
+			content1: strings.Repeat("some-content", 10000) + "a",
+			content2: strings.Repeat("some-content", 10000) + "b",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filetestutils.TempDir(t)
+			path1 := filepath.Join(dir, "file1")
+			path2 := filepath.Join(dir, "file2")
+			assert.NoError(t, os.WriteFile(path1, []byte(tt.content1), 0644))
+			assert.NoError(t, os.WriteFile(path2, []byte(tt.content2), 0644))
+
+			got, err := sameContent(path1, path2)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_fileWriterCollector_NewRun(t *testing.T) {
+	next := &mockReusableCollector{}
+	opts := FileWriterCollectorOptions{
+		OutDir:           "some-out-dir",
+		SkipUnchanged:    true,
+		CleanupUntracked: true,
+	}
+	p := NewFileWriterCollectorWithOpts(opts, next).(*fileWriterCollector)
+	p.generatedFiles["some-file"] = true
+
+	got := p.NewRun().(*fileWriterCollector)
+
+	assert.NotSame(t, p, got)
+	assert.Equal(t, opts, got.opts)
+	assert.Empty(t, got.generatedFiles)
+	assert.Len(t, next.runs, 1)
+	assert.Same(t, next.runs[0], got.next)
+}
+
+func Test_fileWriterCollector_Results(t *testing.T) {
+	outDir := filetestutils.TempDir(t)
+	assert.NoError(t, ioutilx.ReaderToFile(strings.NewReader("some-content"), filepath.Join(outDir, "unchanged")))
+	assert.NoError(t, ioutilx.ReaderToFile(strings.NewReader("some-content"), filepath.Join(outDir, "sub", "untracked")))
+	nextResults := []pipeline.FileResult{{Path: "some-next-path", Status: pipeline.FileSkipped}}
+	next := &mockReportingCollector{results: nextResults}
+	next.On("Collect", mock.Anything).Return(nil)
+	next.On("OnPipelineCompleted").Return(nil)
+	p := NewFileWriterCollectorWithOpts(FileWriterCollectorOptions{
+		OutDir:           outDir,
+		SkipUnchanged:    true,
+		CleanupUntracked: true,
+	}, next).(*fileWriterCollector)
+
+	assert.NoError(t, p.Collect(&pipeline.Template{Path: "written", Reader: io.NopCloser(strings.NewReader("some-new-content"))}))
+	assert.NoError(t, p.Collect(&pipeline.Template{Path: "unchanged", Reader: io.NopCloser(strings.NewReader("some-content"))}))
+	assert.NoError(t, p.OnPipelineCompleted())
+
+	assert.Equal(t, []pipeline.FileResult{
+		{Path: "written", Status: pipeline.FileWritten, Bytes: 16},
+		{Path: "unchanged", Status: pipeline.FileUnchanged, Bytes: 12},
+		{Path: filepath.Join("sub", "untracked"), Status: pipeline.FileRemoved},
+		{Path: "some-next-path", Status: pipeline.FileSkipped},
+	}, p.Results())
+}
+
+func Test_fileWriterCollector_OnPipelineCompleted_Logger(t *testing.T) {
+	outDir := filetestutils.TempDir(t)
+	untrackedPath := filepath.Join(outDir, "untracked-file.txt")
+	assert.NoError(t, os.WriteFile(untrackedPath, []byte("some-content"), 0644))
+	logs := &bytes.Buffer{}
+
+	p := NewFileWriterCollectorWithOpts(FileWriterCollectorOptions{
+		OutDir:           outDir,
+		CleanupUntracked: true,
+		Logger:           slog.New(slog.NewTextHandler(logs, nil)),
+	}, nil)
+
+	assert.NoError(t, p.OnPipelineCompleted())
+	assert.NoFileExists(t, untrackedPath)
+	assert.Contains(t, logs.String(), `level=INFO msg="Removing untracked file" path=`+untrackedPath)
+}
+
+func Test_fileWriterCollector_CollectStream_tracing(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	ctx, parent := recorder.Start(tracing.ContextWithTracer(context.Background(), recorder), "some-template")
+	next := &mockStreamingCollector{}
+	p := NewFileWriterCollector(filetestutils.TempDir(t), next).(*fileWriterCollector)
+
+	err := p.CollectStream((&pipeline.Template{Path: "some-file"}).WithContext(ctx), pipeline.RenderReader(io.NopCloser(strings.NewReader("some-content"))))
+	parent.End()
+
+	assert.NoError(t, err)
+	spans := recorder.SpansNamed("collectors.fileWriter")
+	assert.Len(t, spans, 1)
+	assert.Equal(t, 1, spans[0].ParentID)
+	assert.True(t, spans[0].Ended)
+	status, _ := spans[0].Attribute("status")
+	assert.Equal(t, string(pipeline.FileWritten), status)
+	bytes, _ := spans[0].Attribute("bytes")
+	assert.Equal(t, int64(12), bytes)
+	assert.Equal(t, []string{"some-content"}, next.contents)
+}
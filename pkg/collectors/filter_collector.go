@@ -3,14 +3,20 @@ package collectors
 import (
 	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
 	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 )
 
 type filterCollector struct {
 	baseCollector
 
-	filter filters.Filter
+	filter  filters.Filter
+	skipped []pipeline.FileResult
 }
 
+// NewFilterCollector creates a collector that forwards to the next one only
+// the templates whose path is accepted by the filter. If the filter is a
+// filters.DataFilter, it receives the data of the run (see
+// pipeline.DataFromContext).
 func NewFilterCollector(filter filters.Filter, nextCollector pipeline.Collector) pipeline.Collector {
 	return &filterCollector{
 		filter: filter,
@@ -20,11 +26,55 @@ func NewFilterCollector(filter filters.Filter, nextCollector pipeline.Collector)
 	}
 }
 
+func (p *filterCollector) NewRun() pipeline.Collector {
+	return NewFilterCollector(p.filter, p.nextRun())
+}
+
 func (p *filterCollector) Collect(args *pipeline.Template) error {
-	if p.filter.Accept(args.Path) {
-		return p.next.Collect(args)
+	accepted, err := p.accept(args)
+	if err != nil || !accepted {
+		return err
+	}
+	return p.next.Collect(args)
+}
+
+func (p *filterCollector) CollectStream(args *pipeline.Template, render pipeline.RenderFunc) error {
+	accepted, err := p.accept(args)
+	if err != nil || !accepted {
+		return err
+	}
+	return pipeline.CollectStream(p.next, args, render)
+}
+
+// accept returns whether the filter accepts the template, recording the
+// skipped ones
+func (p *filterCollector) accept(args *pipeline.Template) (bool, error) {
+	_, span := tracing.Start(args.Context(), "collectors.filter", tracing.String("path", args.Path))
+	defer span.End()
+
+	accepted, err := filters.AcceptData(p.filter, args.Path, pipeline.DataFromContext(args.Context()))
+	span.RecordError(err)
+	if err != nil {
+		return false, err
 	}
-	return nil
+	span.SetAttributes(tracing.Bool("accepted", accepted))
+	if !accepted {
+		p.skip(args.Path)
+	}
+	return accepted, nil
+}
+
+func (p *filterCollector) skip(path string) {
+	p.skipped = append(p.skipped, pipeline.FileResult{
+		Path:   path,
+		Status: pipeline.FileSkipped,
+	})
+}
+
+// Results returns the files skipped by the filter in the current run,
+// followed by the results of the next collector
+func (p *filterCollector) Results() []pipeline.FileResult {
+	return append(append([]pipeline.FileResult{}, p.skipped...), p.nextResults()...)
 }
 
 func (p *filterCollector) OnPipelineCompleted() error {
@@ -1,13 +1,17 @@
 package collectors
 
 import (
+	"context"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
 	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 	"github.com/pasdam/go-utils/pkg/assertutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNewFilterCollector(t *testing.T) {
@@ -156,3 +160,135 @@ func Test_filterCollector_OnPipelineCompleted(t *testing.T) {
 		})
 	}
 }
+
+func Test_filterCollector_CollectStream(t *testing.T) {
+	filter, err := filters.NewPatternFilter(true, "some-matching-pattern")
+	assert.NoError(t, err)
+	tests := []struct {
+		name      string
+		path      string
+		wantPaths []string
+	}{
+		{
+			name:      "Should stream template if path is accepted by the filter",
+			path:      "some-matching-pattern",
+			wantPaths: []string{"some-matching-pattern"},
+		},
+		{
+			name: "Should not render template if path is not accepted by the filter",
+			path: "some-not-matching-pattern",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &mockStreamingCollector{}
+			p := NewFilterCollector(filter, next).(*filterCollector)
+			rendered := false
+
+			err := p.CollectStream(&pipeline.Template{Path: tt.path}, func(w io.Writer) error {
+				rendered = true
+				return nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPaths, next.paths)
+			assert.Equal(t, len(tt.wantPaths) > 0, rendered)
+		})
+	}
+}
+
+func Test_filterCollector_NewRun(t *testing.T) {
+	next := &mockReusableCollector{}
+	filter := filters.NewNoOpFilter()
+	p := NewFilterCollector(filter, next).(*filterCollector)
+
+	got := p.NewRun().(*filterCollector)
+
+	assert.NotSame(t, p, got)
+	assert.Equal(t, filter, got.filter)
+	assert.Len(t, next.runs, 1)
+	assert.Same(t, next.runs[0], got.next)
+}
+
+func Test_filterCollector_Results(t *testing.T) {
+	filter, err := filters.NewPatternFilter(true, "some-matching-pattern")
+	assert.NoError(t, err)
+	nextResults := []pipeline.FileResult{{Path: "some-matching-pattern", Status: pipeline.FileWritten, Bytes: 10}}
+	next := &mockReportingCollector{results: nextResults}
+	next.On("Collect", mock.Anything).Return(nil)
+	p := NewFilterCollector(filter, next).(*filterCollector)
+
+	assert.NoError(t, p.Collect(&pipeline.Template{Path: "some-matching-pattern"}))
+	assert.NoError(t, p.Collect(&pipeline.Template{Path: "some-not-matching-path"}))
+	assert.NoError(t, p.CollectStream(&pipeline.Template{Path: "some-other-path"}, nil))
+
+	assert.Equal(t, []pipeline.FileResult{
+		{Path: "some-not-matching-path", Status: pipeline.FileSkipped},
+		{Path: "some-other-path", Status: pipeline.FileSkipped},
+		{Path: "some-matching-pattern", Status: pipeline.FileWritten, Bytes: 10},
+	}, p.Results())
+}
+
+func Test_filterCollector_Collect_tracing(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	ctx := tracing.ContextWithTracer(context.Background(), recorder)
+	filter, _ := filters.NewPatternFilter(true, "some-matching-pattern")
+	next := &mockCollector{}
+	next.On("Collect", mock.Anything).Return(nil)
+	p := NewFilterCollector(filter, next)
+
+	assert.NoError(t, p.Collect((&pipeline.Template{Path: "some-matching-pattern"}).WithContext(ctx)))
+	assert.NoError(t, p.Collect((&pipeline.Template{Path: "some-not-matching-path"}).WithContext(ctx)))
+
+	spans := recorder.SpansNamed("collectors.filter")
+	assert.Len(t, spans, 2)
+	accepted, _ := spans[0].Attribute("accepted")
+	assert.Equal(t, true, accepted)
+	accepted, _ = spans[1].Attribute("accepted")
+	assert.Equal(t, false, accepted)
+}
+
+func Test_filterCollector_Collect_data(t *testing.T) {
+	filter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "grpc/**", Condition: "{{ .features.grpc }}"})
+	assert.NoError(t, err)
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		wantPath []string
+	}{
+		{
+			name:     "Should collect template if the condition is true",
+			data:     map[string]interface{}{"features": map[string]interface{}{"grpc": true}},
+			wantPath: []string{"grpc/server.go"},
+		},
+		{
+			name: "Should not collect template if the condition is false",
+			data: map[string]interface{}{"features": map[string]interface{}{"grpc": false}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &mockStreamingCollector{}
+			p := NewFilterCollector(filter, next)
+			ctx := pipeline.ContextWithData(context.Background(), tt.data)
+
+			err := pipeline.CollectStream(p, (&pipeline.Template{Path: "grpc/server.go"}).WithContext(ctx), func(w io.Writer) error { return nil })
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPath, next.paths)
+		})
+	}
+}
+
+func Test_filterCollector_Collect_ShouldReturnErrorIfTheFilterFails(t *testing.T) {
+	filter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "*", Condition: "{{ index .list 5 }}"})
+	assert.NoError(t, err)
+	next := &mockCollector{}
+	p := NewFilterCollector(filter, next)
+	ctx := pipeline.ContextWithData(context.Background(), map[string]interface{}{"list": []string{}})
+
+	err = p.Collect((&pipeline.Template{Path: "file.txt"}).WithContext(ctx))
+
+	assert.ErrorContains(t, err, `unable to evaluate the condition of "*" for file.txt`)
+	next.AssertNotCalled(t, "Collect", mock.Anything)
+}
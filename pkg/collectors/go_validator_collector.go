@@ -0,0 +1,270 @@
+package collectors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
+)
+
+type GoValidatorCollectorOptions struct {
+	TypeCheck  bool           // Flag to type-check the generated packages in OnPipelineCompleted, in addition to parsing the files
+	ModulePath string         // Module path of the generated files, used to resolve the imports between generated packages
+	Importer   types.Importer // Importer of the packages that are not generated; defaults to importer.Default()
+}
+
+// GoSourceError is an error in a generated Go file, reported against the
+// template that generated it
+type GoSourceError struct {
+	Path   string
+	Source string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *GoSourceError) Error() string {
+	msg := fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Msg)
+	if e.Source != e.Path {
+		msg += fmt.Sprintf(" (generated by template %s)", e.Source)
+	}
+	return msg
+}
+
+type goValidatorCollector struct {
+	baseCollector
+
+	opts    GoValidatorCollectorOptions
+	fset    *token.FileSet
+	files   []*ast.File
+	sources map[string]string // path of the generated files -> path of their templates
+}
+
+// NewGoValidatorCollector creates a collector that parses the generated .go
+// files, failing on syntax errors, and forwards them to the next collector. If
+// TypeCheck is set, the generated packages (the non-test files in the same
+// directory) are type-checked in OnPipelineCompleted, before notifying the
+// next collector. Errors are reported as GoSourceError.
+func NewGoValidatorCollector(opts GoValidatorCollectorOptions, nextCollector pipeline.Collector) pipeline.Collector {
+	return &goValidatorCollector{
+		baseCollector: baseCollector{
+			next: nextCollector,
+		},
+		opts:    opts,
+		fset:    token.NewFileSet(),
+		sources: make(map[string]string),
+	}
+}
+
+// NewRun returns a new collector with the same options, that validates the
+// files generated in a single run.
+func (p *goValidatorCollector) NewRun() pipeline.Collector {
+	return NewGoValidatorCollector(p.opts, p.nextRun())
+}
+
+func (p *goValidatorCollector) Collect(args *pipeline.Template) error {
+	return p.CollectStream(args, pipeline.RenderReader(args.Reader))
+}
+
+func (p *goValidatorCollector) CollectStream(args *pipeline.Template, render pipeline.RenderFunc) error {
+	if path.Ext(args.Path) != ".go" {
+		if p.next == nil {
+			return nil
+		}
+		return pipeline.CollectStream(p.next, args, render)
+	}
+
+	var content bytes.Buffer
+	err := render(&content)
+	if err != nil {
+		return err
+	}
+
+	_, span := tracing.Start(args.Context(), "collectors.goValidator", tracing.String("path", args.Path))
+	source := sourceOf(args)
+	file, err := parser.ParseFile(p.fset, args.Path, content.Bytes(), parser.AllErrors|parser.ParseComments)
+	if err != nil {
+		err = p.sourceErrors(err, map[string]string{args.Path: source})
+	}
+	span.RecordError(err)
+	span.End()
+	if err != nil {
+		return err
+	}
+
+	p.files = append(p.files, file)
+	p.sources[args.Path] = source
+
+	if p.next == nil {
+		return nil
+	}
+	tpl := *args
+	tpl.Reader = nil
+	return pipeline.CollectStream(p.next, &tpl, pipeline.RenderReader(&content))
+}
+
+// Results returns the results of the next collector
+func (p *goValidatorCollector) Results() []pipeline.FileResult {
+	return p.nextResults()
+}
+
+func (p *goValidatorCollector) OnPipelineCompleted() error {
+	if p.opts.TypeCheck {
+		err := p.typeCheck()
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.next == nil {
+		return nil
+	}
+	return p.next.OnPipelineCompleted()
+}
+
+// typeCheck type-checks the generated packages, in order of directory
+func (p *goValidatorCollector) typeCheck() error {
+	packages := make(map[string][]*ast.File)
+	for _, file := range p.files {
+		filename := p.fset.Position(file.Package).Filename
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+		dir := path.Dir(filename)
+		packages[dir] = append(packages[dir], file)
+	}
+
+	dirs := make([]string, 0, len(packages))
+	for dir := range packages {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	checker := &goPackagesChecker{
+		opts:     p.opts,
+		fset:     p.fset,
+		packages: packages,
+		checked:  make(map[string]*types.Package),
+		errs:     make(map[string][]error),
+	}
+	errs := make([]error, 0)
+	for _, dir := range dirs {
+		checker.check(dir)
+		errs = append(errs, checker.errs[dir]...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return p.sourceErrors(errors.Join(errs...), p.sources)
+}
+
+// sourceErrors converts the parsing and type-checking errors into
+// GoSourceErrors
+func (p *goValidatorCollector) sourceErrors(err error, sources map[string]string) error {
+	errs := make([]error, 0)
+	var collect func(err error)
+	collect = func(err error) {
+		var list scanner.ErrorList
+		var typeErr types.Error
+		var joined interface{ Unwrap() []error }
+		switch {
+		case errors.As(err, &joined):
+			for _, err := range joined.Unwrap() {
+				collect(err)
+			}
+		case errors.As(err, &list):
+			for _, err := range list {
+				errs = append(errs, &GoSourceError{
+					Path:   err.Pos.Filename,
+					Source: sources[err.Pos.Filename],
+					Line:   err.Pos.Line,
+					Column: err.Pos.Column,
+					Msg:    err.Msg,
+				})
+			}
+		case errors.As(err, &typeErr):
+			position := typeErr.Fset.Position(typeErr.Pos)
+			errs = append(errs, &GoSourceError{
+				Path:   position.Filename,
+				Source: sources[position.Filename],
+				Line:   position.Line,
+				Column: position.Column,
+				Msg:    typeErr.Msg,
+			})
+		default:
+			errs = append(errs, err)
+		}
+	}
+	collect(err)
+	return errors.Join(errs...)
+}
+
+// goPackagesChecker type-checks the generated packages, resolving the imports
+// between them through the module path
+type goPackagesChecker struct {
+	opts     GoValidatorCollectorOptions
+	fset     *token.FileSet
+	packages map[string][]*ast.File // directory -> files
+	checked  map[string]*types.Package
+	errs     map[string][]error
+	importer types.Importer
+}
+
+func (c *goPackagesChecker) check(dir string) *types.Package {
+	if pkg, ok := c.checked[dir]; ok {
+		return pkg
+	}
+	c.checked[dir] = nil // guard against import cycles
+
+	config := &types.Config{
+		Importer: c,
+		Error: func(err error) {
+			c.errs[dir] = append(c.errs[dir], err)
+		},
+	}
+	pkg, _ := config.Check(dir, c.fset, c.packages[dir], nil)
+	c.checked[dir] = pkg
+	return pkg
+}
+
+// Import resolves the generated packages, and the others with the importer
+// of the options
+func (c *goPackagesChecker) Import(importPath string) (*types.Package, error) {
+	if len(c.opts.ModulePath) > 0 {
+		dir := ""
+		if importPath != c.opts.ModulePath {
+			dir = strings.TrimPrefix(importPath, c.opts.ModulePath+"/")
+		}
+		if dir != importPath {
+			if dir == "" {
+				dir = "."
+			}
+			if _, ok := c.packages[dir]; ok {
+				pkg := c.check(dir)
+				if pkg == nil {
+					return nil, fmt.Errorf("import cycle or invalid package %s", importPath)
+				}
+				return pkg, nil
+			}
+		}
+	}
+
+	if c.importer == nil {
+		c.importer = c.opts.Importer
+		if c.importer == nil {
+			c.importer = importer.Default()
+		}
+	}
+	return c.importer.Import(importPath)
+}
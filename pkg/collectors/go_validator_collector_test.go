@@ -0,0 +1,178 @@
+package collectors
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func collectFiles(t *testing.T, p pipeline.Collector, files map[string]string, order ...string) error {
+	for _, path := range order {
+		err := p.Collect(&pipeline.Template{
+			Path:   path,
+			Source: path + ".tmpl",
+			Reader: io.NopCloser(strings.NewReader(files[path])),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Test_goValidatorCollector_Collect(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		content      string
+		wantContents []string
+		wantErr      error
+	}{
+		{
+			name:         "Should forward valid Go file",
+			path:         "pkg/main.go",
+			content:      "package main\n\nfunc main() {}\n",
+			wantContents: []string{"package main\n\nfunc main() {}\n"},
+		},
+		{
+			name:         "Should forward other files without parsing them",
+			path:         "README.md",
+			content:      "package {",
+			wantContents: []string{"package {"},
+		},
+		{
+			name:    "Should report syntax errors against the template",
+			path:    "pkg/main.go",
+			content: "package main\n\nfunc main() {\n",
+			wantErr: errors.Join(
+				&GoSourceError{Path: "pkg/main.go", Source: "pkg/main.go.tmpl", Line: 3, Column: 15, Msg: "expected ';', found 'EOF'"},
+				&GoSourceError{Path: "pkg/main.go", Source: "pkg/main.go.tmpl", Line: 3, Column: 15, Msg: "expected '}', found 'EOF'"},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &readingCollector{}
+			p := NewGoValidatorCollector(GoValidatorCollectorOptions{}, next)
+
+			err := collectFiles(t, p, map[string]string{tt.path: tt.content}, tt.path)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantContents, next.contents)
+		})
+	}
+}
+
+func Test_goValidatorCollector_OnPipelineCompleted(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      GoValidatorCollectorOptions
+		files     map[string]string
+		order     []string
+		wantErr   string
+		wantNotes []string
+	}{
+		{
+			name: "Should not type-check if disabled",
+			opts: GoValidatorCollectorOptions{},
+			files: map[string]string{
+				"main.go": "package main\n\nfunc main() { undefined() }\n",
+			},
+			order: []string{"main.go"},
+		},
+		{
+			name: "Should type-check generated packages",
+			opts: GoValidatorCollectorOptions{TypeCheck: true},
+			files: map[string]string{
+				"main.go":  "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(helper()) }\n",
+				"other.go": "package main\n\nfunc helper() string { return \"\" }\n",
+			},
+			order: []string{"main.go", "other.go"},
+		},
+		{
+			name: "Should report type errors against the templates",
+			opts: GoValidatorCollectorOptions{TypeCheck: true},
+			files: map[string]string{
+				"a/a.go": "package a\n\nfunc A() int { return \"\" }\n",
+				"b/b.go": "package b\n\nfunc B() { undefined() }\n",
+			},
+			order:   []string{"b/b.go", "a/a.go"},
+			wantErr: "a/a.go:3:23: cannot use \"\" (untyped string constant) as int value in return statement (generated by template a/a.go.tmpl)\nb/b.go:3:12: undefined: undefined (generated by template b/b.go.tmpl)",
+		},
+		{
+			name: "Should resolve imports between generated packages",
+			opts: GoValidatorCollectorOptions{TypeCheck: true, ModulePath: "example.com/project"},
+			files: map[string]string{
+				"main.go":          "package main\n\nimport \"example.com/project/pkg/util\"\n\nfunc main() { _ = util.Name() }\n",
+				"pkg/util/util.go": "package util\n\nfunc Name() string { return \"\" }\n",
+			},
+			order: []string{"main.go", "pkg/util/util.go"},
+		},
+		{
+			name: "Should report errors in imported generated packages",
+			opts: GoValidatorCollectorOptions{TypeCheck: true, ModulePath: "example.com/project"},
+			files: map[string]string{
+				"main.go":          "package main\n\nimport \"example.com/project/pkg/util\"\n\nfunc main() { _ = util.Missing() }\n",
+				"pkg/util/util.go": "package util\n\nfunc Name() string { return \"\" }\n",
+			},
+			order:   []string{"main.go", "pkg/util/util.go"},
+			wantErr: "main.go:5:24: undefined: util.Missing (generated by template main.go.tmpl)",
+		},
+		{
+			name: "Should skip test files",
+			opts: GoValidatorCollectorOptions{TypeCheck: true},
+			files: map[string]string{
+				"util.go":      "package util\n",
+				"util_test.go": "package util_test\n\nfunc f() { undefined() }\n",
+			},
+			order: []string{"util.go", "util_test.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &readingCollector{}
+			next.On("OnPipelineCompleted").Return(nil)
+			p := NewGoValidatorCollector(tt.opts, next)
+
+			assert.NoError(t, collectFiles(t, p, tt.files, tt.order...))
+			err := p.OnPipelineCompleted()
+
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, err, tt.wantErr)
+				var sourceErr *GoSourceError
+				assert.ErrorAs(t, err, &sourceErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_goValidatorCollector_OnPipelineCompleted_Next(t *testing.T) {
+	next := &mockCollector{}
+	next.On("OnPipelineCompleted").Return(errors.New("some-error"))
+
+	assert.Equal(t, errors.New("some-error"), NewGoValidatorCollector(GoValidatorCollectorOptions{TypeCheck: true}, next).OnPipelineCompleted())
+	assert.NoError(t, NewGoValidatorCollector(GoValidatorCollectorOptions{}, nil).OnPipelineCompleted())
+}
+
+func Test_goValidatorCollector_NewRun(t *testing.T) {
+	next := &mockReusableCollector{}
+	next.On("Collect", mock.Anything).Return(nil)
+	opts := GoValidatorCollectorOptions{TypeCheck: true}
+	p := NewGoValidatorCollector(opts, next).(*goValidatorCollector)
+	assert.NoError(t, collectFiles(t, p, map[string]string{"main.go": "package main\n"}, "main.go"))
+
+	got := p.NewRun().(*goValidatorCollector)
+
+	assert.NotSame(t, p, got)
+	assert.Equal(t, opts, got.opts)
+	assert.Empty(t, got.files)
+	assert.Len(t, next.runs, 1)
+	assert.Same(t, next.runs[0], got.next)
+}
@@ -0,0 +1,11 @@
+package collectors
+
+import "log/slog"
+
+// loggerOrDefault returns the specified logger, or slog.Default() if nil
+func loggerOrDefault(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
@@ -0,0 +1,181 @@
+package collectors
+
+import (
+	"bytes"
+	"io/fs"
+	"sync"
+	"testing/fstest"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// GeneratedFile is a file collected by a MemoryCollector
+type GeneratedFile struct {
+	Path    string      // Path of the file
+	Source  string      // Path of the main template that generated the file
+	Origin  string      // Origin of the main template, see pipeline.Template
+	Mode    fs.FileMode // Permission bits of the file; defaults to 0644
+	Content []byte      // Content of the file
+}
+
+// MemoryCollector stores the generated files in memory, so that they can be
+// inspected after the run without writing them to disk, i.e. in tests or to
+// return them in an API response. The collector is safe for concurrent use.
+//
+// The collector is not reusable: when a pipeline is reused, the files of all
+// the runs are stored, use Reset to clear them between runs.
+type MemoryCollector struct {
+	baseCollector
+
+	mu    sync.Mutex
+	files []*GeneratedFile
+	index map[string]int
+}
+
+// NewMemoryCollector creates a collector that stores the generated files in
+// memory, and forwards them to nextCollector, if any
+func NewMemoryCollector(nextCollector pipeline.Collector) *MemoryCollector {
+	return &MemoryCollector{
+		baseCollector: baseCollector{
+			next: nextCollector,
+		},
+		index: make(map[string]int),
+	}
+}
+
+func (p *MemoryCollector) Collect(args *pipeline.Template) error {
+	return p.CollectStream(args, pipeline.RenderReader(args.Reader))
+}
+
+// CollectStream stores the content of the template, and forwards it to the
+// next collector, if any. A template with the same path of a previous one
+// replaces its content, keeping its position.
+func (p *MemoryCollector) CollectStream(args *pipeline.Template, render pipeline.RenderFunc) error {
+	var content bytes.Buffer
+	err := render(&content)
+	if err != nil {
+		return err
+	}
+
+	mode := args.Mode.Perm()
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	p.store(&GeneratedFile{
+		Path:    args.Path,
+		Source:  sourceOf(args),
+		Origin:  args.Origin,
+		Mode:    mode,
+		Content: content.Bytes(),
+	})
+
+	if p.next == nil {
+		return nil
+	}
+	tpl := *args
+	tpl.Reader = nil
+	return pipeline.CollectStream(p.next, &tpl, pipeline.RenderReader(bytes.NewReader(content.Bytes())))
+}
+
+func (p *MemoryCollector) store(file *GeneratedFile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if i, ok := p.index[file.Path]; ok {
+		p.files[i] = file
+		return
+	}
+	p.index[file.Path] = len(p.files)
+	p.files = append(p.files, file)
+}
+
+// Files returns a copy of the collected files, in the order they were
+// collected
+func (p *MemoryCollector) Files() []GeneratedFile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	files := make([]GeneratedFile, 0, len(p.files))
+	for _, file := range p.files {
+		files = append(files, copyGeneratedFile(file))
+	}
+	return files
+}
+
+// File returns a copy of the file with the specified path, if collected
+func (p *MemoryCollector) File(path string) (GeneratedFile, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i, ok := p.index[path]
+	if !ok {
+		return GeneratedFile{}, false
+	}
+	return copyGeneratedFile(p.files[i]), true
+}
+
+// Contents returns the content of the collected files, by path
+func (p *MemoryCollector) Contents() map[string]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	contents := make(map[string]string, len(p.files))
+	for _, file := range p.files {
+		contents[file.Path] = string(file.Content)
+	}
+	return contents
+}
+
+// FS returns the collected files as a file system
+func (p *MemoryCollector) FS() fstest.MapFS {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fsys := make(fstest.MapFS, len(p.files))
+	for _, file := range p.files {
+		fsys[file.Path] = &fstest.MapFile{
+			Data: bytes.Clone(file.Content),
+			Mode: file.Mode,
+		}
+	}
+	return fsys
+}
+
+// Reset removes the collected files
+func (p *MemoryCollector) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.files = nil
+	p.index = make(map[string]int)
+}
+
+// Results returns the collected files, in order, followed by the results of
+// the next collector
+func (p *MemoryCollector) Results() []pipeline.FileResult {
+	p.mu.Lock()
+	results := make([]pipeline.FileResult, 0, len(p.files))
+	for _, file := range p.files {
+		results = append(results, pipeline.FileResult{
+			Path:   file.Path,
+			Status: pipeline.FileWritten,
+			Bytes:  int64(len(file.Content)),
+		})
+	}
+	p.mu.Unlock()
+
+	return append(results, p.nextResults()...)
+}
+
+func (p *MemoryCollector) OnPipelineCompleted() error {
+	if p.next == nil {
+		return nil
+	}
+	return p.next.OnPipelineCompleted()
+}
+
+func copyGeneratedFile(file *GeneratedFile) GeneratedFile {
+	copied := *file
+	copied.Content = bytes.Clone(file.Content)
+	return copied
+}
@@ -0,0 +1,117 @@
+package collectors
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func collectString(t *testing.T, p pipeline.Collector, tpl *pipeline.Template, content string) {
+	tpl.Reader = io.NopCloser(strings.NewReader(content))
+	assert.NoError(t, p.Collect(tpl))
+}
+
+func TestMemoryCollector_Collect(t *testing.T) {
+	p := NewMemoryCollector(nil)
+
+	collectString(t, p, &pipeline.Template{Path: "c.txt"}, "some-content")
+	collectString(t, p, &pipeline.Template{Path: "b.sh", Source: "all.yaml", Origin: "team", Mode: 0755}, "some-script")
+	collectString(t, p, &pipeline.Template{Path: "a/c.txt"}, "other-content")
+	collectString(t, p, &pipeline.Template{Path: "c.txt", Mode: 0600}, "new-content")
+
+	assert.Equal(t, []GeneratedFile{
+		{Path: "c.txt", Source: "c.txt", Mode: 0600, Content: []byte("new-content")},
+		{Path: "b.sh", Source: "all.yaml", Origin: "team", Mode: 0755, Content: []byte("some-script")},
+		{Path: "a/c.txt", Source: "a/c.txt", Mode: 0644, Content: []byte("other-content")},
+	}, p.Files())
+	assert.Equal(t, map[string]string{
+		"c.txt":   "new-content",
+		"b.sh":    "some-script",
+		"a/c.txt": "other-content",
+	}, p.Contents())
+	assert.Equal(t, fstest.MapFS{
+		"c.txt":   {Data: []byte("new-content"), Mode: 0600},
+		"b.sh":    {Data: []byte("some-script"), Mode: 0755},
+		"a/c.txt": {Data: []byte("other-content"), Mode: 0644},
+	}, p.FS())
+	assert.NoError(t, fstest.TestFS(p.FS(), "c.txt", "b.sh", "a/c.txt"))
+
+	file, ok := p.File("b.sh")
+	assert.True(t, ok)
+	assert.Equal(t, GeneratedFile{Path: "b.sh", Source: "all.yaml", Origin: "team", Mode: 0755, Content: []byte("some-script")}, file)
+	_, ok = p.File("missing.txt")
+	assert.False(t, ok)
+
+	assert.NoError(t, p.OnPipelineCompleted())
+	p.Reset()
+	assert.Equal(t, []GeneratedFile{}, p.Files())
+	_, ok = p.File("b.sh")
+	assert.False(t, ok)
+}
+
+func TestMemoryCollector_Files_returnsCopies(t *testing.T) {
+	p := NewMemoryCollector(nil)
+	collectString(t, p, &pipeline.Template{Path: "a.txt"}, "abc")
+
+	files := p.Files()
+	files[0].Content[0] = 'x'
+	p.FS()["a.txt"].Data[1] = 'x'
+
+	assert.Equal(t, map[string]string{"a.txt": "abc"}, p.Contents())
+}
+
+func TestMemoryCollector_CollectStream(t *testing.T) {
+	next := &readingCollector{}
+	next.On("OnPipelineCompleted").Return(errors.New("some-error"))
+	p := NewMemoryCollector(next)
+
+	err := p.CollectStream(&pipeline.Template{Path: "a.txt"}, func(w io.Writer) error {
+		_, err := w.Write([]byte("some-content"))
+		return err
+	})
+	assert.NoError(t, err)
+	err = p.CollectStream(&pipeline.Template{Path: "b.txt"}, func(w io.Writer) error { return errors.New("some-render-error") })
+	assert.Equal(t, errors.New("some-render-error"), err)
+
+	assert.Equal(t, []string{"some-content"}, next.contents)
+	assert.Equal(t, map[string]string{"a.txt": "some-content"}, p.Contents())
+	assert.Equal(t, errors.New("some-error"), p.OnPipelineCompleted())
+}
+
+func TestMemoryCollector_Results(t *testing.T) {
+	next := &mockReportingCollector{results: []pipeline.FileResult{{Path: "some-path", Status: pipeline.FileSkipped}}}
+	next.On("Collect", mock.Anything).Return(nil)
+	p := NewMemoryCollector(next)
+
+	collectString(t, p, &pipeline.Template{Path: "b.txt"}, "some-content")
+	collectString(t, p, &pipeline.Template{Path: "a.txt"}, "abc")
+
+	assert.Equal(t, []pipeline.FileResult{
+		{Path: "b.txt", Status: pipeline.FileWritten, Bytes: 12},
+		{Path: "a.txt", Status: pipeline.FileWritten, Bytes: 3},
+		{Path: "some-path", Status: pipeline.FileSkipped},
+	}, p.Results())
+}
+
+func TestMemoryCollector_concurrentCollect(t *testing.T) {
+	p := NewMemoryCollector(nil)
+
+	var wg sync.WaitGroup
+	for _, path := range []string{"a.txt", "b.txt", "c.txt", "d.txt"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, p.Collect(&pipeline.Template{Path: path, Reader: io.NopCloser(strings.NewReader(path))}))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string]string{"a.txt": "a.txt", "b.txt": "b.txt", "c.txt": "c.txt", "d.txt": "d.txt"}, p.Contents())
+}
@@ -1,6 +1,10 @@
 package collectors
 
 import (
+	"context"
+	"io"
+	"strings"
+
 	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
 	"github.com/stretchr/testify/mock"
 )
@@ -18,3 +22,61 @@ func (m *mockCollector) OnPipelineCompleted() error {
 	args := m.Called()
 	return args.Error(0)
 }
+
+// mockStreamingCollector records the content of the collected templates,
+// rendering the ones whose path is not in discard
+type mockStreamingCollector struct {
+	mockCollector
+
+	discard  map[string]bool
+	paths    []string
+	contents []string
+}
+
+func (m *mockStreamingCollector) CollectStream(tpl *pipeline.Template, render pipeline.RenderFunc) error {
+	if m.discard[tpl.Path] {
+		return nil
+	}
+	var content strings.Builder
+	err := render(&content)
+	m.paths = append(m.paths, tpl.Path)
+	m.contents = append(m.contents, content.String())
+	return err
+}
+
+// mockReusableCollector returns a new instance at each run, keeping track of them
+type mockReusableCollector struct {
+	mockCollector
+
+	runs []*mockReusableCollector
+}
+
+func (m *mockReusableCollector) NewRun() pipeline.Collector {
+	run := &mockReusableCollector{}
+	m.runs = append(m.runs, run)
+	return run
+}
+
+// mockReportingCollector reports the specified results
+type mockReportingCollector struct {
+	mockCollector
+
+	results []pipeline.FileResult
+}
+
+func (m *mockReportingCollector) Results() []pipeline.FileResult {
+	return m.results
+}
+
+// contextRecordingCollector records the context of the collected templates
+type contextRecordingCollector struct {
+	mockCollector
+
+	contexts []context.Context
+}
+
+func (m *contextRecordingCollector) Collect(tpl *pipeline.Template) error {
+	m.contexts = append(m.contexts, tpl.Context())
+	_, err := io.Copy(io.Discard, tpl.Reader)
+	return err
+}
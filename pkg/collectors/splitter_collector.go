@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
 	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 )
 
 var (
@@ -18,14 +19,24 @@ var (
 	defaultMultiFileTemplateHeadersPrefix = "@@ "  // TODO: make this configurable
 )
 
+type SplitterCollectorOptions struct {
+	Logger *slog.Logger // Logger used by the collector; defaults to slog.Default()
+}
+
 type SplitterCollector struct {
 	baseCollector
 
 	headerPrefix string
 	filter       filters.Filter
+	logger       *slog.Logger
 }
 
 func NewSplitterCollector(nextCollector pipeline.Collector) *SplitterCollector {
+	return NewSplitterCollectorWithOpts(SplitterCollectorOptions{}, nextCollector)
+}
+
+// NewSplitterCollectorWithOpts creates a splitter collector with the provided options
+func NewSplitterCollectorWithOpts(opts SplitterCollectorOptions, nextCollector pipeline.Collector) *SplitterCollector {
 	filter, _ := filters.NewPatternFilter(true, fmt.Sprintf("^%s.*", defaultMultiFileTemplateNamePrefix))
 
 	return &SplitterCollector{
@@ -34,6 +45,18 @@ func NewSplitterCollector(nextCollector pipeline.Collector) *SplitterCollector {
 		},
 		headerPrefix: defaultMultiFileTemplateHeadersPrefix,
 		filter:       filter,
+		logger:       opts.Logger,
+	}
+}
+
+func (p *SplitterCollector) NewRun() pipeline.Collector {
+	return &SplitterCollector{
+		baseCollector: baseCollector{
+			next: p.nextRun(),
+		},
+		headerPrefix: p.headerPrefix,
+		filter:       p.filter,
+		logger:       p.logger,
 	}
 }
 
@@ -42,41 +65,100 @@ func (p *SplitterCollector) Collect(args *pipeline.Template) error {
 		return p.next.Collect(args)
 	}
 
-	scanner := bufio.NewScanner(args.Reader)
+	return p.split(args, args.Reader)
+}
+
+// CollectStream splits the content while it is rendered, streaming each file
+// to the next collector.
+func (p *SplitterCollector) CollectStream(args *pipeline.Template, render pipeline.RenderFunc) error {
+	if !p.filter.Accept(filepath.Base(args.Path)) {
+		return pipeline.CollectStream(p.next, args, render)
+	}
+
+	reader, writer := io.Pipe()
+	renderDone := make(chan struct{})
+	go func() {
+		defer close(renderDone)
+		writer.CloseWithError(render(writer))
+	}()
+
+	err := p.split(args, reader)
+	// Unblock the rendering, in case the split stopped before reading all the content
+	reader.Close()
+	<-renderDone
+
+	return err
+}
+
+// split reads the content of a multi-file template, and collects each file,
+// streaming its content to the next collector while it is read.
+func (p *SplitterCollector) split(args *pipeline.Template, reader io.Reader) (err error) {
+	templatePath := args.Path
+	ctx, span := tracing.Start(args.Context(), "collectors.splitter", tracing.String("path", templatePath))
+	files := 0
+	defer func() {
+		span.SetAttributes(tracing.Int("files", files))
+		span.RecordError(err)
+		span.End()
+	}()
+
+	scanner := bufio.NewScanner(reader)
 	scanner.Split(scanLines)
-	var buffer *bytes.Buffer
-	var currentTemplate *pipeline.Template
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, p.headerPrefix) { // is header, this indicates a new file
-			if currentTemplate != nil { // collect previous file
-				err := p.next.Collect(currentTemplate)
-				if err != nil {
-					return err
-				}
-			}
-			buffer = &bytes.Buffer{}
-			currentTemplate = &pipeline.Template{
-				Reader: io.NopCloser(buffer),
-				Path:   strings.ReplaceAll(strings.TrimPrefix(strings.TrimSpace(line), "@@ name="), "\"", ""), // TODO
-			}
-		} else if buffer == nil {
-			slog.Error("Invalid first line", slog.String("templatePath", args.Path), slog.String("line", line))
+	if !scanner.Scan() {
+		return scanner.Err()
+	}
+
+	line := scanner.Text()
+	for {
+		if !strings.HasPrefix(line, p.headerPrefix) { // a header indicates a new file
+			loggerOrDefault(p.logger).Error("Invalid first line", slog.String("templatePath", templatePath), slog.String("line", line))
 			return fmt.Errorf("invalid first line")
+		}
 
-		} else {
-			buffer.WriteString(line)
+		currentTemplate := (&pipeline.Template{
+			Path:   strings.ReplaceAll(strings.TrimPrefix(strings.TrimSpace(line), "@@ name="), "\"", ""), // TODO
+			Source: sourceOf(args),
+			Origin: args.Origin,
+			Mode:   args.Mode,
+		}).WithContext(ctx)
+		nextHeader := false
+		copyFile := func(w io.Writer) error {
+			for scanner.Scan() {
+				line = scanner.Text()
+				if strings.HasPrefix(line, p.headerPrefix) {
+					nextHeader = true
+					return nil
+				}
+				if w != nil {
+					_, err := io.WriteString(w, line)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			return scanner.Err()
 		}
-	}
 
-	if currentTemplate != nil {
-		err := p.next.Collect(currentTemplate)
+		rendered := false
+		err = pipeline.CollectStream(p.next, currentTemplate, func(w io.Writer) error {
+			rendered = true
+			return copyFile(w)
+		})
 		if err != nil {
 			return err
 		}
-	}
+		files++
+		if !rendered { // the next collector discarded the file, skip its content
+			err = copyFile(nil)
+			if err != nil {
+				return err
+			}
+		}
 
-	return nil
+		if !nextHeader {
+			return nil
+		}
+	}
 }
 
 func (p *SplitterCollector) CreateHeaderWithName(name string) string {
@@ -105,6 +187,11 @@ func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
 	return 0, nil, nil
 }
 
+// Results returns the results of the next collector
+func (p *SplitterCollector) Results() []pipeline.FileResult {
+	return p.nextResults()
+}
+
 func (p *SplitterCollector) OnPipelineCompleted() error {
 	if p.next == nil {
 		return nil
@@ -1,12 +1,16 @@
 package collectors
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"io"
+	"log/slog"
 	"strings"
 	"testing"
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 	"github.com/pasdam/go-utils/pkg/assertutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -249,3 +253,154 @@ func Test_splitterCollector_CreateHeaderWithName(t *testing.T) {
 		})
 	}
 }
+
+func Test_splitterCollector_CollectStream(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		content      string
+		renderErr    error
+		discard      map[string]bool
+		wantPaths    []string
+		wantContents []string
+		wantErr      error
+	}{
+		{
+			name:         "Should stream template as is if the name prefix is not the expected one",
+			path:         "some-path/something",
+			content:      "@@ name=\"some-other-name-1\"\nsome-content-1\n",
+			wantPaths:    []string{"some-path/something"},
+			wantContents: []string{"@@ name=\"some-other-name-1\"\nsome-content-1\n"},
+		},
+		{
+			name:         "Should stream separate files",
+			path:         "some-path/mul_something",
+			content:      "@@ name=\"some-other-name-1\"\nsome-file-1-line-1\nsome-file-1-line-2\n@@ name=\"some-other-name-2\"\nsome-file-2-line-1\n\n",
+			wantPaths:    []string{"some-other-name-1", "some-other-name-2"},
+			wantContents: []string{"some-file-1-line-1\nsome-file-1-line-2\n", "some-file-2-line-1\n\n"},
+		},
+		{
+			name:         "Should skip content of files discarded by the next collector",
+			path:         "some-path/mul_something",
+			content:      "@@ name=\"some-other-name-1\"\nsome-content-1\n@@ name=\"some-other-name-2\"\nsome-content-2\n@@ name=\"some-other-name-3\"\nsome-content-3",
+			discard:      map[string]bool{"some-other-name-2": true},
+			wantPaths:    []string{"some-other-name-1", "some-other-name-3"},
+			wantContents: []string{"some-content-1\n", "some-content-3"},
+		},
+		{
+			name:    "Should return error if the first line is not a header",
+			path:    "some-path/mul_something",
+			content: "some-content-1\n@@ name=\"some-other-name-1\"\n",
+			wantErr: errors.New("invalid first line"),
+		},
+		{
+			name:      "Should propagate render error",
+			path:      "some-path/mul_something",
+			content:   "@@ name=\"some-other-name-1\"\nsome-content-1\n",
+			renderErr: errors.New("some-render-error"),
+			wantErr:   errors.New("some-render-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &mockStreamingCollector{discard: tt.discard}
+			p := NewSplitterCollector(next)
+
+			err := p.CollectStream(&pipeline.Template{Path: tt.path}, func(w io.Writer) error {
+				_, err := io.WriteString(w, tt.content)
+				if err != nil {
+					return err
+				}
+				return tt.renderErr
+			})
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.wantPaths, next.paths)
+				assert.Equal(t, tt.wantContents, next.contents)
+			}
+		})
+	}
+}
+
+func Test_splitterCollector_NewRun(t *testing.T) {
+	next := &mockReusableCollector{}
+	p := NewSplitterCollector(next)
+
+	got := p.NewRun().(*SplitterCollector)
+
+	assert.NotSame(t, p, got)
+	assert.Equal(t, p.headerPrefix, got.headerPrefix)
+	assert.Equal(t, p.filter, got.filter)
+	assert.Equal(t, p.logger, got.logger)
+	assert.Len(t, next.runs, 1)
+	assert.Same(t, next.runs[0], got.next)
+}
+
+func Test_splitterCollector_Results(t *testing.T) {
+	nextResults := []pipeline.FileResult{{Path: "some-path", Status: pipeline.FileWritten, Bytes: 10}}
+
+	assert.Equal(t, nextResults, NewSplitterCollector(&mockReportingCollector{results: nextResults}).Results())
+	assert.Nil(t, NewSplitterCollector(&mockCollector{}).Results())
+	assert.Nil(t, NewSplitterCollector(nil).Results())
+}
+
+func TestNewSplitterCollectorWithOpts(t *testing.T) {
+	logs := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(logs, nil))
+	next := &mockCollector{}
+
+	p := NewSplitterCollectorWithOpts(SplitterCollectorOptions{Logger: logger}, next)
+	err := p.Collect(&pipeline.Template{
+		Path:   "mul_something",
+		Reader: io.NopCloser(strings.NewReader("some-invalid-header")),
+	})
+
+	assert.Equal(t, errors.New("invalid first line"), err)
+	assert.Same(t, logger, p.logger)
+	assert.Equal(t, next, p.next)
+	assert.Contains(t, logs.String(), `level=ERROR msg="Invalid first line" templatePath=mul_something line=some-invalid-header`)
+}
+
+func Test_splitterCollector_Collect_tracing(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	ctx := tracing.ContextWithTracer(context.Background(), recorder)
+	next := &contextRecordingCollector{}
+	p := NewSplitterCollector(next)
+
+	err := p.Collect((&pipeline.Template{
+		Path:   "mul_something",
+		Reader: io.NopCloser(strings.NewReader("@@ name=\"file-1\"\nline-1\n@@ name=\"file-2\"\nline-2")),
+	}).WithContext(ctx))
+
+	assert.NoError(t, err)
+	spans := recorder.SpansNamed("collectors.splitter")
+	assert.Len(t, spans, 1)
+	files, _ := spans[0].Attribute("files")
+	assert.Equal(t, 2, files)
+	assert.True(t, spans[0].Ended)
+
+	// the split files carry the context of the splitter span
+	assert.Len(t, next.contexts, 2)
+	_, child := tracing.Start(next.contexts[0], "some-child")
+	child.End()
+	assert.Equal(t, spans[0].ID, recorder.SpansNamed("some-child")[0].ParentID)
+}
+
+func Test_splitterCollector_Collect_metadata(t *testing.T) {
+	next := NewMemoryCollector(nil)
+	p := NewSplitterCollector(next)
+
+	err := p.Collect(&pipeline.Template{
+		Path:   "mul_something",
+		Origin: "some-layer",
+		Mode:   0755,
+		Reader: io.NopCloser(strings.NewReader("@@ name=\"file-1\"\nline-1\n@@ name=\"file-2\"\nline-2")),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []GeneratedFile{
+		{Path: "file-1", Source: "mul_something", Origin: "some-layer", Mode: 0755, Content: []byte("line-1\n")},
+		{Path: "file-2", Source: "mul_something", Origin: "some-layer", Mode: 0755, Content: []byte("line-2")},
+	}, next.Files())
+}
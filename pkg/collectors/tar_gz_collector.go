@@ -0,0 +1,49 @@
+package collectors
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"time"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// NewTarGzCollector creates a collector that writes the templates into a
+// gzip-compressed tar archive on the writer, finalized when the pipeline
+// completes; see NewZipCollector for the content of the archive.
+func NewTarGzCollector(w io.Writer, opts ArchiveCollectorOptions, nextCollector pipeline.Collector) pipeline.Collector {
+	gzipWriter := gzip.NewWriter(w)
+	return newArchiveCollector(&tarGzArchiveWriter{
+		gzipWriter: gzipWriter,
+		tarWriter:  tar.NewWriter(gzipWriter),
+	}, opts, nextCollector)
+}
+
+type tarGzArchiveWriter struct {
+	gzipWriter *gzip.Writer
+	tarWriter  *tar.Writer
+}
+
+func (w *tarGzArchiveWriter) writeEntry(entry *archiveEntry, modTime time.Time) error {
+	err := w.tarWriter.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     entry.path,
+		Mode:     int64(entry.mode),
+		Size:     int64(len(entry.content)),
+		ModTime:  modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.tarWriter.Write(entry.content)
+	return err
+}
+
+func (w *tarGzArchiveWriter) close() error {
+	err := w.tarWriter.Close()
+	if err != nil {
+		return err
+	}
+	return w.gzipWriter.Close()
+}
@@ -0,0 +1,101 @@
+package collectors
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// teeCollector delivers each template to multiple independent branches, the
+// content is rendered once and each branch reads its own copy of it.
+type teeCollector struct {
+	branches []pipeline.Collector
+}
+
+// NewTeeCollector creates a collector that delivers each template to all the
+// branches, in order. The content of each template is buffered once, and each
+// branch receives an independent reader (or render function, for streaming
+// collectors). Errors of the branches are joined, all the branches receive the
+// template even if one of them fails.
+func NewTeeCollector(branches ...pipeline.Collector) pipeline.Collector {
+	return &teeCollector{
+		branches: branches,
+	}
+}
+
+// NewRun returns a tee collector with a new run of each branch
+func (p *teeCollector) NewRun() pipeline.Collector {
+	branches := make([]pipeline.Collector, 0, len(p.branches))
+	for _, branch := range p.branches {
+		branches = append(branches, pipeline.NewCollectorRun(branch))
+	}
+	return NewTeeCollector(branches...)
+}
+
+func (p *teeCollector) Collect(args *pipeline.Template) error {
+	return p.CollectStream(args, pipeline.RenderReader(args.Reader))
+}
+
+// CollectStream renders the template in a buffer the first time a branch
+// requests its content, and replays it for the other branches. If no branch
+// requests the content, the template is not rendered.
+func (p *teeCollector) CollectStream(args *pipeline.Template, render pipeline.RenderFunc) error {
+	content := &bufferedRender{render: render}
+
+	errs := make([]error, 0)
+	for _, branch := range p.branches {
+		tpl := *args
+		tpl.Reader = nil
+		err := pipeline.CollectStream(branch, &tpl, content.Render)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Results returns the results of the branches, in order
+func (p *teeCollector) Results() []pipeline.FileResult {
+	var results []pipeline.FileResult
+	for _, branch := range p.branches {
+		results = append(results, pipeline.CollectorResults(branch)...)
+	}
+	return results
+}
+
+// OnPipelineCompleted notifies all the branches, joining their errors
+func (p *teeCollector) OnPipelineCompleted() error {
+	errs := make([]error, 0)
+	for _, branch := range p.branches {
+		err := branch.OnPipelineCompleted()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bufferedRender renders the content into a buffer on the first invocation,
+// and copies it at each invocation
+type bufferedRender struct {
+	render   pipeline.RenderFunc
+	rendered bool
+	content  bytes.Buffer
+	err      error
+}
+
+func (r *bufferedRender) Render(w io.Writer) error {
+	if !r.rendered {
+		r.rendered = true
+		r.err = r.render(&r.content)
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	_, err := w.Write(r.content.Bytes())
+	return err
+}
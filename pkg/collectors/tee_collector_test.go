@@ -0,0 +1,155 @@
+package collectors
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// readingCollector reads the content of the collected templates
+type readingCollector struct {
+	mockCollector
+
+	contents []string
+}
+
+func (m *readingCollector) Collect(tpl *pipeline.Template) error {
+	content, err := io.ReadAll(tpl.Reader)
+	m.contents = append(m.contents, string(content))
+	return err
+}
+
+func Test_teeCollector_Collect(t *testing.T) {
+	first := &readingCollector{}
+	second := &mockStreamingCollector{}
+	third := &readingCollector{}
+	p := NewTeeCollector(first, second, third)
+
+	err := p.Collect(&pipeline.Template{
+		Path:   "some-path",
+		Reader: io.NopCloser(strings.NewReader("some-content")),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"some-content"}, first.contents)
+	assert.Equal(t, []string{"some-path"}, second.paths)
+	assert.Equal(t, []string{"some-content"}, second.contents)
+	assert.Equal(t, []string{"some-content"}, third.contents)
+}
+
+func Test_teeCollector_CollectStream(t *testing.T) {
+	tests := []struct {
+		name         string
+		discard      bool
+		renderErr    error
+		branchErr    error
+		wantRenders  int
+		wantContents []string
+		wantErr      error
+	}{
+		{
+			name:         "Should render once for all the branches",
+			wantRenders:  1,
+			wantContents: []string{"some-content"},
+		},
+		{
+			name:        "Should not render if the branches discard the template",
+			discard:     true,
+			wantRenders: 0,
+		},
+		{
+			name:         "Should report render error to all the branches",
+			renderErr:    errors.New("some-render-error"),
+			wantRenders:  1,
+			wantContents: []string{""},
+			wantErr:      errors.Join(errors.New("some-render-error"), errors.New("some-render-error")),
+		},
+		{
+			name:         "Should deliver template to all branches if one fails",
+			branchErr:    errors.New("some-branch-error"),
+			wantRenders:  1,
+			wantContents: []string{"some-content"},
+			wantErr:      errors.Join(errors.New("some-branch-error")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := &mockCollector{}
+			if !tt.discard && tt.renderErr == nil {
+				first.On("Collect", mock.Anything).Return(tt.branchErr)
+			}
+			second := &mockStreamingCollector{discard: map[string]bool{}}
+			if tt.discard {
+				first = nil
+				second.discard["some-path"] = true
+			}
+			branches := []pipeline.Collector{second}
+			if first != nil {
+				branches = []pipeline.Collector{first, second}
+			}
+			renders := 0
+
+			err := NewTeeCollector(branches...).(*teeCollector).CollectStream(&pipeline.Template{Path: "some-path"}, func(w io.Writer) error {
+				renders++
+				if tt.renderErr != nil {
+					return tt.renderErr
+				}
+				_, err := io.WriteString(w, "some-content")
+				return err
+			})
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantRenders, renders)
+			assert.Equal(t, tt.wantContents, second.contents)
+			if first != nil && tt.renderErr == nil {
+				first.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func Test_teeCollector_OnPipelineCompleted(t *testing.T) {
+	first := &mockCollector{}
+	first.On("OnPipelineCompleted").Return(errors.New("some-error"))
+	second := &mockCollector{}
+	second.On("OnPipelineCompleted").Return(nil)
+	third := &mockCollector{}
+	third.On("OnPipelineCompleted").Return(errors.New("some-other-error"))
+
+	err := NewTeeCollector(first, second, third).OnPipelineCompleted()
+
+	assert.Equal(t, errors.Join(errors.New("some-error"), errors.New("some-other-error")), err)
+	first.AssertExpectations(t)
+	second.AssertExpectations(t)
+	third.AssertExpectations(t)
+	assert.NoError(t, NewTeeCollector().OnPipelineCompleted())
+}
+
+func Test_teeCollector_NewRun(t *testing.T) {
+	reusable := &mockReusableCollector{}
+	other := &mockCollector{}
+	p := NewTeeCollector(reusable, other).(*teeCollector)
+
+	got := p.NewRun().(*teeCollector)
+
+	assert.NotSame(t, p, got)
+	assert.Len(t, reusable.runs, 1)
+	assert.Equal(t, []pipeline.Collector{reusable.runs[0], other}, got.branches)
+}
+
+func Test_teeCollector_Results(t *testing.T) {
+	first := []pipeline.FileResult{{Path: "file1", Status: pipeline.FileWritten}}
+	second := []pipeline.FileResult{{Path: "file2", Status: pipeline.FileSkipped}}
+	p := NewTeeCollector(
+		&mockReportingCollector{results: first},
+		&mockCollector{},
+		&mockReportingCollector{results: second},
+	).(*teeCollector)
+
+	assert.Equal(t, append(first, second...), p.Results())
+}
@@ -0,0 +1,120 @@
+package collectors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
+)
+
+// Transformer transforms the content of the file with the specified path
+type Transformer func(path string, content []byte) ([]byte, error)
+
+// TransformError is returned when a transformer fails, it reports the path of
+// the transformed file and of the template that generated it
+type TransformError struct {
+	Path   string
+	Source string
+	Err    error
+}
+
+func (e *TransformError) Error() string {
+	if e.Source != e.Path {
+		return fmt.Sprintf("unable to transform %s (generated by template %s): %s", e.Path, e.Source, e.Err.Error())
+	}
+	return fmt.Sprintf("unable to transform %s: %s", e.Path, e.Err.Error())
+}
+
+func (e *TransformError) Unwrap() error {
+	return e.Err
+}
+
+type transformerCollector struct {
+	baseCollector
+
+	filter      filters.Filter
+	transformer Transformer
+}
+
+// NewTransformerCollector creates a collector that applies the transformer to
+// the content of the templates whose path is accepted by the filter, and
+// forwards them to the next collector; other templates are forwarded as they
+// are. If the filter is a filters.DataFilter, it receives the data of the run
+// (see pipeline.DataFromContext). The content of the transformed templates is
+// buffered, as transformers need all of it.
+func NewTransformerCollector(filter filters.Filter, transformer Transformer, nextCollector pipeline.Collector) pipeline.Collector {
+	return &transformerCollector{
+		baseCollector: baseCollector{
+			next: nextCollector,
+		},
+		filter:      filter,
+		transformer: transformer,
+	}
+}
+
+func (p *transformerCollector) NewRun() pipeline.Collector {
+	return NewTransformerCollector(p.filter, p.transformer, p.nextRun())
+}
+
+func (p *transformerCollector) Collect(args *pipeline.Template) error {
+	return p.CollectStream(args, pipeline.RenderReader(args.Reader))
+}
+
+// CollectStream transforms the template, if accepted by the filter, and
+// forwards it to the next collector. If there is no next collector, the
+// template is only transformed, i.e. to validate its content.
+func (p *transformerCollector) CollectStream(args *pipeline.Template, render pipeline.RenderFunc) error {
+	accepted, err := filters.AcceptData(p.filter, args.Path, pipeline.DataFromContext(args.Context()))
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		if p.next == nil {
+			return nil
+		}
+		return pipeline.CollectStream(p.next, args, render)
+	}
+
+	var content bytes.Buffer
+	err = render(&content)
+	if err != nil {
+		return err
+	}
+
+	_, span := tracing.Start(args.Context(), "collectors.transformer", tracing.String("path", args.Path))
+	transformed, err := p.transformer(args.Path, content.Bytes())
+	if err != nil {
+		err = &TransformError{Path: args.Path, Source: sourceOf(args), Err: err}
+	}
+	span.RecordError(err)
+	span.End()
+	if err != nil {
+		return err
+	}
+
+	if p.next == nil {
+		return nil
+	}
+
+	tpl := *args
+	tpl.Reader = nil
+	return pipeline.CollectStream(p.next, &tpl, func(w io.Writer) error {
+		_, err := w.Write(transformed)
+		return err
+	})
+}
+
+// Results returns the results of the next collector
+func (p *transformerCollector) Results() []pipeline.FileResult {
+	return p.nextResults()
+}
+
+func (p *transformerCollector) OnPipelineCompleted() error {
+	if p.next == nil {
+		return nil
+	}
+	return p.next.OnPipelineCompleted()
+}
@@ -0,0 +1,181 @@
+package collectors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func upperTransformer(path string, content []byte) ([]byte, error) {
+	if bytes.Contains(content, []byte("invalid")) {
+		return nil, errors.New("some-error")
+	}
+	return bytes.ToUpper(content), nil
+}
+
+func Test_transformerCollector_Collect(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        *pipeline.Template
+		content     string
+		wantContent []string
+		wantErr     error
+	}{
+		{
+			name:        "Should transform accepted template",
+			args:        &pipeline.Template{Path: "file.txt"},
+			content:     "some-content",
+			wantContent: []string{"SOME-CONTENT"},
+		},
+		{
+			name:        "Should forward other templates as they are",
+			args:        &pipeline.Template{Path: "file.go"},
+			content:     "some-content",
+			wantContent: []string{"some-content"},
+		},
+		{
+			name:    "Should report transformation error against the path",
+			args:    &pipeline.Template{Path: "file.txt"},
+			content: "invalid",
+			wantErr: errors.New("unable to transform file.txt: some-error"),
+		},
+		{
+			name:    "Should report transformation error against the source template",
+			args:    &pipeline.Template{Path: "file.txt", Source: "mul_files"},
+			content: "invalid",
+			wantErr: errors.New("unable to transform file.txt (generated by template mul_files): some-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, _ := filters.NewPatternFilter(true, `\.txt$`)
+			next := &readingCollector{}
+			p := NewTransformerCollector(filter, upperTransformer, next)
+			tt.args.Reader = io.NopCloser(strings.NewReader(tt.content))
+
+			err := p.Collect(tt.args)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantContent, next.contents)
+		})
+	}
+}
+
+func Test_transformerCollector_CollectStream(t *testing.T) {
+	filter, _ := filters.NewPatternFilter(true, `\.txt$`)
+	next := &mockStreamingCollector{}
+	p := NewTransformerCollector(filter, upperTransformer, next).(*transformerCollector)
+
+	err := p.CollectStream(&pipeline.Template{Path: "file.txt"}, pipeline.RenderReader(strings.NewReader("some-content")))
+	assert.NoError(t, err)
+	err = p.CollectStream(&pipeline.Template{Path: "file.txt"}, func(w io.Writer) error { return errors.New("some-render-error") })
+	assert.Equal(t, errors.New("some-render-error"), err)
+
+	assert.Equal(t, []string{"file.txt"}, next.paths)
+	assert.Equal(t, []string{"SOME-CONTENT"}, next.contents)
+}
+
+func Test_transformerCollector_CollectStream_data(t *testing.T) {
+	filter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "*.txt", Condition: "{{ .format }}"})
+	assert.NoError(t, err)
+	tests := []struct {
+		name        string
+		data        map[string]interface{}
+		wantContent []string
+	}{
+		{
+			name:        "Should transform template if the condition is true",
+			data:        map[string]interface{}{"format": true},
+			wantContent: []string{"SOME-CONTENT"},
+		},
+		{
+			name:        "Should forward template as it is if the condition is false",
+			data:        map[string]interface{}{"format": false},
+			wantContent: []string{"some-content"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &mockStreamingCollector{}
+			p := NewTransformerCollector(filter, upperTransformer, next)
+			ctx := pipeline.ContextWithData(context.Background(), tt.data)
+
+			err := pipeline.CollectStream(p, (&pipeline.Template{Path: "file.txt"}).WithContext(ctx), pipeline.RenderReader(strings.NewReader("some-content")))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantContent, next.contents)
+		})
+	}
+}
+
+func Test_transformerCollector_Collect_ShouldReturnErrorIfTheFilterFails(t *testing.T) {
+	filter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "*", Condition: "{{ index .list 5 }}"})
+	assert.NoError(t, err)
+	next := &mockStreamingCollector{}
+	p := NewTransformerCollector(filter, upperTransformer, next)
+	ctx := pipeline.ContextWithData(context.Background(), map[string]interface{}{"list": []string{}})
+
+	err = p.Collect((&pipeline.Template{Path: "file.txt", Reader: io.NopCloser(strings.NewReader("some-content"))}).WithContext(ctx))
+
+	assert.ErrorContains(t, err, `unable to evaluate the condition of "*" for file.txt`)
+	assert.Empty(t, next.paths)
+}
+
+func Test_transformerCollector_WithoutNext(t *testing.T) {
+	filter, _ := filters.NewPatternFilter(true, `\.txt$`)
+	p := NewTransformerCollector(filter, upperTransformer, nil)
+
+	assert.NoError(t, p.Collect(&pipeline.Template{Path: "file.txt", Reader: io.NopCloser(strings.NewReader("some-content"))}))
+	assert.NoError(t, p.Collect(&pipeline.Template{Path: "file.go", Reader: io.NopCloser(strings.NewReader("some-content"))}))
+	assert.Error(t, p.Collect(&pipeline.Template{Path: "file.txt", Reader: io.NopCloser(strings.NewReader("invalid"))}))
+	assert.NoError(t, p.OnPipelineCompleted())
+}
+
+func Test_transformerCollector_SplitFiles(t *testing.T) {
+	filter, _ := filters.NewPatternFilter(true, `\.txt$`)
+	next := &readingCollector{}
+	p := NewChainBuilder().Split().Transform(filter, upperTransformer).Then(func(pipeline.Collector) pipeline.Collector { return next }).Build()
+
+	err := p.Collect(&pipeline.Template{
+		Path:   "mul_files",
+		Reader: io.NopCloser(strings.NewReader("@@ name=\"file1.txt\"\nsome-content\n@@ name=\"file2.txt\"\ninvalid")),
+	})
+
+	assert.Equal(t, &TransformError{Path: "file2.txt", Source: "mul_files", Err: errors.New("some-error")}, err)
+	assert.Equal(t, []string{"SOME-CONTENT\n"}, next.contents)
+}
+
+func Test_transformerCollector_OnPipelineCompleted(t *testing.T) {
+	next := &mockCollector{}
+	next.On("OnPipelineCompleted").Return(errors.New("some-error"))
+
+	err := NewTransformerCollector(filters.NewNoOpFilter(), upperTransformer, next).OnPipelineCompleted()
+
+	assert.Equal(t, errors.New("some-error"), err)
+}
+
+func Test_transformerCollector_NewRun(t *testing.T) {
+	next := &mockReusableCollector{}
+	p := NewTransformerCollector(filters.NewNoOpFilter(), upperTransformer, next).(*transformerCollector)
+
+	got := p.NewRun().(*transformerCollector)
+
+	assert.NotSame(t, p, got)
+	assert.Equal(t, p.filter, got.filter)
+	assert.Len(t, next.runs, 1)
+	assert.Same(t, next.runs[0], got.next)
+}
+
+func Test_transformerCollector_Results(t *testing.T) {
+	results := []pipeline.FileResult{{Path: "some-path", Status: pipeline.FileWritten}}
+
+	assert.Equal(t, results, NewTransformerCollector(filters.NewNoOpFilter(), upperTransformer, &mockReportingCollector{results: results}).(*transformerCollector).Results())
+}
@@ -0,0 +1,44 @@
+package collectors
+
+import (
+	"archive/zip"
+	"io"
+	"time"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// NewZipCollector creates a collector that writes the templates into a zip
+// archive on the writer, finalized when the pipeline completes. The entries
+// are sorted by path, with the modification time of the options and the mode
+// of the templates (0644 if not set), so that the archive is reproducible.
+// The collector keeps the content of the templates in memory until the
+// pipeline completes, and it can be used for a single run: once the archive is
+// written, it returns an error.
+func NewZipCollector(w io.Writer, opts ArchiveCollectorOptions, nextCollector pipeline.Collector) pipeline.Collector {
+	return newArchiveCollector(&zipArchiveWriter{writer: zip.NewWriter(w)}, opts, nextCollector)
+}
+
+type zipArchiveWriter struct {
+	writer *zip.Writer
+}
+
+func (w *zipArchiveWriter) writeEntry(entry *archiveEntry, modTime time.Time) error {
+	header := &zip.FileHeader{
+		Name:     entry.path,
+		Method:   zip.Deflate,
+		Modified: modTime,
+	}
+	header.SetMode(entry.mode)
+
+	writer, err := w.writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(entry.content)
+	return err
+}
+
+func (w *zipArchiveWriter) close() error {
+	return w.writer.Close()
+}
@@ -0,0 +1,53 @@
+package filters
+
+import "fmt"
+
+type andFilter struct {
+	filters []Filter
+}
+
+// And returns a new Filter that merges the input ones, it performs a logical
+// AND (it returns true only if all the filters accept the value). The data
+// is passed to the filters that depend on it, see DataFilter.
+func And(filters ...Filter) Filter {
+	return &andFilter{
+		filters: filters,
+	}
+}
+
+func (f *andFilter) Accept(value string) bool {
+	for _, filter := range f.filters {
+		if !filter.Accept(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *andFilter) AcceptData(value string, data map[string]interface{}) (bool, error) {
+	for _, filter := range f.filters {
+		accepted, err := AcceptData(filter, value, data)
+		if err != nil || !accepted {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Explain returns the explanation of the first filter that rejects the value,
+// or of the last one if all of them accept it
+func (f *andFilter) Explain(value string, data map[string]interface{}) (Explanation, error) {
+	explanation := Explanation{Accepted: true, Filter: "and", Index: -1}
+	for i, filter := range f.filters {
+		var err error
+		explanation, err = Explain(filter, value, data)
+		if err != nil {
+			return Explanation{}, err
+		}
+		explanation = explanation.under(fmt.Sprintf("and[%d]", i))
+		if !explanation.Accepted {
+			break
+		}
+	}
+	return explanation, nil
+}
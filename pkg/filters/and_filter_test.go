@@ -0,0 +1,30 @@
+package filters_test
+
+import (
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_andFilter_Accept_ShouldReturnFalseIfAFilterDoesNotAcceptTheFile(t *testing.T) {
+	filter := filters.And(
+		&mockFilter{"match"},
+		&mockFilter{"to-match"},
+		&mockFilter{"file-to-exclude"},
+	)
+	assert.False(t, filter.Accept("file-to-match"))
+}
+
+func Test_andFilter_Accept_ShouldReturnTrueIfAllFiltersAcceptTheFile(t *testing.T) {
+	filter := filters.And(
+		&mockFilter{"match"},
+		&mockFilter{"to-match"},
+		&mockFilter{"file-to-match"},
+	)
+	assert.True(t, filter.Accept("file-to-match"))
+}
+
+func Test_andFilter_Accept_ShouldReturnTrueIfThereAreNoFilters(t *testing.T) {
+	assert.True(t, filters.And().Accept("file"))
+}
@@ -0,0 +1,106 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ConditionRule is a rule of a condition filter
+type ConditionRule struct {
+	Pattern   string // Glob pattern of the paths the rule applies to, see NewGlobFilter
+	Condition string // Template evaluated with the data, i.e. "{{ .Values.features.grpc }}"; the matching paths are rejected if the output is falsy
+}
+
+type ConditionFilterOptions struct {
+	Functions template.FuncMap // Functions available to the conditions, in addition to the builtin ones; optional
+}
+
+// conditionRule is a parsed ConditionRule
+type conditionRule struct {
+	pattern   string
+	filter    Filter
+	condition *template.Template
+}
+
+type conditionFilter struct {
+	rules []conditionRule
+}
+
+// NewConditionFilter returns a new DataFilter that accepts a path unless it
+// matches the pattern of a rule whose condition is false. The conditions are
+// templates evaluated with the data of the run, and they are false if their
+// output, trimmed, is empty, "false", "0", "no", "off" or "<no value>" (the
+// output of missing values). Accept evaluates the conditions without data.
+// It returns an error if a pattern or a condition is invalid.
+func NewConditionFilter(rules ...ConditionRule) (DataFilter, error) {
+	return NewConditionFilterWithOpts(ConditionFilterOptions{}, rules...)
+}
+
+// NewConditionFilterWithOpts returns a new condition filter, see
+// NewConditionFilter, with the specified options
+func NewConditionFilterWithOpts(opts ConditionFilterOptions, rules ...ConditionRule) (DataFilter, error) {
+	parsed := make([]conditionRule, 0, len(rules))
+	for _, rule := range rules {
+		filter, err := NewGlobFilter(true, rule.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		condition, err := template.New(rule.Pattern).Funcs(opts.Functions).Parse(rule.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition for %q: %w", rule.Pattern, err)
+		}
+		parsed = append(parsed, conditionRule{
+			pattern:   rule.Pattern,
+			filter:    filter,
+			condition: condition,
+		})
+	}
+
+	return &conditionFilter{
+		rules: parsed,
+	}, nil
+}
+
+func (f *conditionFilter) Accept(value string) bool {
+	accepted, err := f.AcceptData(value, nil)
+	return accepted && err == nil
+}
+
+func (f *conditionFilter) AcceptData(value string, data map[string]interface{}) (bool, error) {
+	explanation, err := f.Explain(value, data)
+	return explanation.Accepted, err
+}
+
+// Explain returns the rule whose condition is false, if any, or the last rule
+// that matches the value
+func (f *conditionFilter) Explain(value string, data map[string]interface{}) (Explanation, error) {
+	explanation := Explanation{Accepted: true, Filter: "condition", Index: -1}
+	for i, rule := range f.rules {
+		if !rule.filter.Accept(value) {
+			continue
+		}
+
+		var output strings.Builder
+		err := rule.condition.Execute(&output, data)
+		if err != nil {
+			return Explanation{}, fmt.Errorf("unable to evaluate the condition of %q for %s: %w", rule.pattern, value, err)
+		}
+		explanation.Index = i
+		explanation.Pattern = rule.pattern
+		if isFalsy(output.String()) {
+			explanation.Accepted = false
+			break
+		}
+	}
+	return explanation, nil
+}
+
+// isFalsy returns whether the output of a condition means false
+func isFalsy(output string) bool {
+	switch strings.ToLower(strings.TrimSpace(output)) {
+	case "", "false", "0", "no", "off", "<no value>":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package filters_test
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_conditionFilter_AcceptData(t *testing.T) {
+	rules := []filters.ConditionRule{
+		{Pattern: "grpc/", Condition: "{{ .Values.features.grpc }}"},
+		{Pattern: "*.sql", Condition: `{{ eq .Values.database "postgres" }}`},
+	}
+	tests := []struct {
+		name  string
+		path  string
+		value interface{}
+		want  bool
+	}{
+		{name: "Should accept a path that matches no rule", path: "main.go", want: true},
+		{name: "Should accept a path whose condition is true", path: "grpc/server.go", value: true, want: true},
+		{name: "Should accept a path whose condition is a non falsy string", path: "grpc/server.go", value: "enabled", want: true},
+		{name: "Should reject a path whose condition is false", path: "grpc/server.go", value: false, want: false},
+		{name: "Should reject a path whose condition is zero", path: "grpc/server.go", value: 0, want: false},
+		{name: "Should reject a path whose condition is no", path: "grpc/server.go", value: " No ", want: false},
+		{name: "Should reject a path whose condition is off", path: "grpc/server.go", value: "off", want: false},
+		{name: "Should reject a path whose condition is empty", path: "grpc/server.go", value: "", want: false},
+		{name: "Should reject a path whose condition is a missing value", path: "grpc/server.go", want: false},
+		{name: "Should reject a path whose condition uses builtin functions", path: "db/schema.sql", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := filters.NewConditionFilter(rules...)
+			assert.NoError(t, err)
+			features := map[string]interface{}{}
+			if tt.value != nil {
+				features["grpc"] = tt.value
+			}
+			data := map[string]interface{}{"Values": map[string]interface{}{"features": features, "database": "mysql"}}
+
+			got, err := filter.AcceptData(tt.path, data)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_conditionFilter_Accept_ShouldEvaluateTheConditionsWithoutData(t *testing.T) {
+	filter, err := filters.NewConditionFilter(
+		filters.ConditionRule{Pattern: "grpc/**", Condition: "{{ .Values.features.grpc }}"},
+		filters.ConditionRule{Pattern: "*.md", Condition: "true"},
+	)
+	assert.NoError(t, err)
+
+	assert.True(t, filter.Accept("README.md"))
+	assert.False(t, filter.Accept("grpc/server.go"))
+}
+
+func Test_conditionFilter_AcceptData_ShouldReturnErrorIfAConditionFails(t *testing.T) {
+	filter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "*.go", Condition: "{{ index .list 1 }}"})
+	assert.NoError(t, err)
+
+	got, err := filter.AcceptData("main.go", map[string]interface{}{"list": []string{}})
+
+	assert.EqualError(t, err, `unable to evaluate the condition of "*.go" for main.go: template: *.go:1:3: executing "*.go" at <index .list 1>: error calling index: index out of range: 1`)
+	assert.False(t, got)
+	assert.False(t, filter.Accept("main.go"))
+}
+
+func TestNewConditionFilterWithOpts_ShouldUseTheFunctions(t *testing.T) {
+	filter, err := filters.NewConditionFilterWithOpts(
+		filters.ConditionFilterOptions{Functions: template.FuncMap{"lower": strings.ToLower}},
+		filters.ConditionRule{Pattern: "*.go", Condition: "{{ lower .enabled }}"},
+	)
+	assert.NoError(t, err)
+
+	got, err := filter.AcceptData("main.go", map[string]interface{}{"enabled": "FALSE"})
+
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestNewConditionFilter_Fail(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    filters.ConditionRule
+		wantErr string
+	}{
+		{
+			name:    "Should return an error if the pattern is invalid",
+			rule:    filters.ConditionRule{Pattern: "[z-a]", Condition: "true"},
+			wantErr: "invalid glob pattern \"[z-a]\": error parsing regexp: invalid character class range: `z-a`",
+		},
+		{
+			name:    "Should return an error if the condition is invalid",
+			rule:    filters.ConditionRule{Pattern: "*.go", Condition: "{{ .enabled"},
+			wantErr: `invalid condition for "*.go": template: *.go:1: unclosed action`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := filters.NewConditionFilter(tt.rule)
+
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Nil(t, filter)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package filters
+
+// DataFilter is a Filter whose decision also depends on the data of the
+// pipeline run, i.e. on the values of the scaffold
+type DataFilter interface {
+	Filter
+
+	// AcceptData returns true if the input string should be accepted with the
+	// specified data, false otherwise, or an error if the decision can't be
+	// taken
+	AcceptData(value string, data map[string]interface{}) (bool, error)
+}
+
+// AcceptData returns whether the filter accepts the value with the specified
+// data: it uses AcceptData if the filter is a DataFilter, Accept otherwise
+func AcceptData(filter Filter, value string, data map[string]interface{}) (bool, error) {
+	if dataFilter, ok := filter.(DataFilter); ok {
+		return dataFilter.AcceptData(value, data)
+	}
+	return filter.Accept(value), nil
+}
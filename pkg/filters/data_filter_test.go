@@ -0,0 +1,58 @@
+package filters_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptData(t *testing.T) {
+	grpc, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "grpc/**", Condition: "{{ .grpc }}"})
+	assert.NoError(t, err)
+	failing := &mockDataFilter{err: errors.New("some-error")}
+	enabled := map[string]interface{}{"grpc": true}
+	tests := []struct {
+		name    string
+		filter  filters.Filter
+		path    string
+		data    map[string]interface{}
+		want    bool
+		wantErr string
+	}{
+		{name: "Should use Accept if the filter doesn't use data", filter: &mockFilter{"main.go"}, path: "main.go", want: true},
+		{name: "Should pass the data to a data filter", filter: grpc, path: "grpc/server.go", data: enabled, want: true},
+		{name: "Should pass the data through And", filter: filters.And(&mockFilter{".go"}, grpc), path: "grpc/server.go", data: enabled, want: true},
+		{name: "Should pass the data through Or", filter: filters.Or(&mockFilter{".md"}, grpc), path: "grpc/server.go", data: enabled, want: true},
+		{name: "Should pass the data through Not", filter: filters.Not(grpc), path: "grpc/server.go", data: enabled, want: false},
+		{name: "Should reject with And if a filter rejects", filter: filters.And(grpc, &mockFilter{".md"}), path: "grpc/server.go", data: enabled, want: false},
+		{name: "Should return the errors of And", filter: filters.And(&mockFilter{".go"}, failing), path: "main.go", wantErr: "some-error"},
+		{name: "Should return the errors of Or", filter: filters.Or(&mockFilter{".md"}, failing), path: "main.go", wantErr: "some-error"},
+		{name: "Should return the errors of Not", filter: filters.Not(failing), path: "main.go", wantErr: "some-error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filters.AcceptData(tt.filter, tt.path, tt.data)
+
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type mockDataFilter struct {
+	err error
+}
+
+func (m *mockDataFilter) Accept(value string) bool {
+	return false
+}
+
+func (m *mockDataFilter) AcceptData(value string, data map[string]interface{}) (bool, error) {
+	return false, m.err
+}
@@ -0,0 +1,89 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explanation describes why a filter accepted or rejected a value
+type Explanation struct {
+	Accepted bool   // Whether the value is accepted
+	Path     string // Position of the filter that decided in the tree of combinators, i.e. "and[1].not"; empty for the root
+	Filter   string // Kind of the filter that decided, i.e. "glob" or "pattern"
+	Mode     string // "inclusive" or "exclusive" for the pattern and glob filters, empty otherwise
+	Index    int    // Index of the pattern (or rule) that decided, -1 if none
+	Pattern  string // Pattern (or rule) that decided, empty if none
+}
+
+// ExplainableFilter is a Filter that can explain its decisions, all the
+// filters of this package implement it
+type ExplainableFilter interface {
+	Filter
+
+	// Explain returns whether the value is accepted with the specified data
+	// (nil if not available), and why
+	Explain(value string, data map[string]interface{}) (Explanation, error)
+}
+
+// Explain returns whether the filter accepts the value with the specified
+// data, and why. Filters that are not explainable are described by their
+// type only.
+func Explain(filter Filter, value string, data map[string]interface{}) (Explanation, error) {
+	if explainable, ok := filter.(ExplainableFilter); ok {
+		return explainable.Explain(value, data)
+	}
+
+	accepted, err := AcceptData(filter, value, data)
+	if err != nil {
+		return Explanation{}, err
+	}
+	return Explanation{
+		Accepted: accepted,
+		Filter:   fmt.Sprintf("%T", filter),
+		Index:    -1,
+	}, nil
+}
+
+// Reason returns the description of the filter and pattern that decided,
+// i.e. `glob filter (exclusive) at and[1], pattern 2 "*.log" matched`
+func (e Explanation) Reason() string {
+	var builder strings.Builder
+	builder.WriteString(e.Filter + " filter")
+	if len(e.Mode) > 0 {
+		builder.WriteString(" (" + e.Mode + ")")
+	}
+	if len(e.Path) > 0 {
+		builder.WriteString(" at " + e.Path)
+	}
+	if e.Index >= 0 {
+		fmt.Fprintf(&builder, ", pattern %d %q matched", e.Index, e.Pattern)
+	} else {
+		builder.WriteString(", no pattern matched")
+	}
+	return builder.String()
+}
+
+func (e Explanation) String() string {
+	if e.Accepted {
+		return "accepted by " + e.Reason()
+	}
+	return "rejected by " + e.Reason()
+}
+
+// under returns the explanation of a filter that is a child of a combinator
+func (e Explanation) under(path string) Explanation {
+	if len(e.Path) == 0 {
+		e.Path = path
+	} else {
+		e.Path = path + "." + e.Path
+	}
+	return e
+}
+
+// filterMode returns the mode of a pattern or glob filter
+func filterMode(inclusive bool) string {
+	if inclusive {
+		return "inclusive"
+	}
+	return "exclusive"
+}
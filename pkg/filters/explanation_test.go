@@ -0,0 +1,174 @@
+package filters_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	glob, err := filters.NewGlobFilter(false, "# comment", "*.log", "build/", "!keep.log")
+	assert.NoError(t, err)
+	pattern, err := filters.NewPatternFilter(true, `\.md$`, `\.go$`)
+	assert.NoError(t, err)
+	condition, err := filters.NewConditionFilter(
+		filters.ConditionRule{Pattern: "*.go", Condition: "true"},
+		filters.ConditionRule{Pattern: "grpc/**", Condition: "{{ .grpc }}"},
+	)
+	assert.NoError(t, err)
+	tests := []struct {
+		name   string
+		filter filters.Filter
+		path   string
+		data   map[string]interface{}
+		want   filters.Explanation
+		reason string
+	}{
+		{
+			name:   "Should explain a glob filter with a matching pattern",
+			filter: glob,
+			path:   "debug.log",
+			want:   filters.Explanation{Accepted: false, Filter: "glob", Mode: "exclusive", Index: 1, Pattern: "*.log"},
+			reason: `rejected by glob filter (exclusive), pattern 1 "*.log" matched`,
+		},
+		{
+			name:   "Should explain a glob filter with a negated pattern",
+			filter: glob,
+			path:   "keep.log",
+			want:   filters.Explanation{Accepted: true, Filter: "glob", Mode: "exclusive", Index: 3, Pattern: "!keep.log"},
+			reason: `accepted by glob filter (exclusive), pattern 3 "!keep.log" matched`,
+		},
+		{
+			name:   "Should explain a glob filter with a matching folder",
+			filter: glob,
+			path:   "build/keep.log",
+			want:   filters.Explanation{Accepted: false, Filter: "glob", Mode: "exclusive", Index: 2, Pattern: "build/"},
+			reason: `rejected by glob filter (exclusive), pattern 2 "build/" matched`,
+		},
+		{
+			name:   "Should explain a glob filter without matching patterns",
+			filter: glob,
+			path:   "main.go",
+			want:   filters.Explanation{Accepted: true, Filter: "glob", Mode: "exclusive", Index: -1},
+			reason: "accepted by glob filter (exclusive), no pattern matched",
+		},
+		{
+			name:   "Should explain a pattern filter with a matching pattern",
+			filter: pattern,
+			path:   "main.go",
+			want:   filters.Explanation{Accepted: true, Filter: "pattern", Mode: "inclusive", Index: 1, Pattern: `\.go$`},
+			reason: `accepted by pattern filter (inclusive), pattern 1 "\\.go$" matched`,
+		},
+		{
+			name:   "Should explain a pattern filter without matching patterns",
+			filter: pattern,
+			path:   "file.txt",
+			want:   filters.Explanation{Accepted: false, Filter: "pattern", Mode: "inclusive", Index: -1},
+			reason: "rejected by pattern filter (inclusive), no pattern matched",
+		},
+		{
+			name:   "Should explain a condition filter with a false condition",
+			filter: condition,
+			path:   "grpc/server.go",
+			data:   map[string]interface{}{"grpc": false},
+			want:   filters.Explanation{Accepted: false, Filter: "condition", Index: 1, Pattern: "grpc/**"},
+			reason: `rejected by condition filter, pattern 1 "grpc/**" matched`,
+		},
+		{
+			name:   "Should explain a condition filter with true conditions",
+			filter: condition,
+			path:   "grpc/server.go",
+			data:   map[string]interface{}{"grpc": true},
+			want:   filters.Explanation{Accepted: true, Filter: "condition", Index: 1, Pattern: "grpc/**"},
+			reason: `accepted by condition filter, pattern 1 "grpc/**" matched`,
+		},
+		{
+			name:   "Should explain a no-op filter",
+			filter: filters.NewNoOpFilter(),
+			path:   "main.go",
+			want:   filters.Explanation{Accepted: true, Filter: "noop", Index: -1},
+			reason: "accepted by noop filter, no pattern matched",
+		},
+		{
+			name:   "Should explain a custom filter with its type",
+			filter: &mockFilter{"main.go"},
+			path:   "main.go",
+			want:   filters.Explanation{Accepted: true, Filter: "*filters_test.mockFilter", Index: -1},
+			reason: "accepted by *filters_test.mockFilter filter, no pattern matched",
+		},
+		{
+			name:   "Should explain the first filter that rejects with And",
+			filter: filters.And(pattern, filters.Not(glob)),
+			path:   "main.go",
+			want:   filters.Explanation{Accepted: false, Path: "and[1].not", Filter: "glob", Mode: "exclusive", Index: -1},
+			reason: "rejected by glob filter (exclusive) at and[1].not, no pattern matched",
+		},
+		{
+			name:   "Should explain the last filter if all accept with And",
+			filter: filters.And(pattern, glob),
+			path:   "main.go",
+			want:   filters.Explanation{Accepted: true, Path: "and[1]", Filter: "glob", Mode: "exclusive", Index: -1},
+			reason: "accepted by glob filter (exclusive) at and[1], no pattern matched",
+		},
+		{
+			name:   "Should explain the first filter that accepts with Or",
+			filter: filters.Or(filters.Not(glob), pattern),
+			path:   "main.go",
+			want:   filters.Explanation{Accepted: true, Path: "or[1]", Filter: "pattern", Mode: "inclusive", Index: 1, Pattern: `\.go$`},
+			reason: `accepted by pattern filter (inclusive) at or[1], pattern 1 "\\.go$" matched`,
+		},
+		{
+			name:   "Should explain an empty And",
+			filter: filters.And(),
+			path:   "main.go",
+			want:   filters.Explanation{Accepted: true, Filter: "and", Index: -1},
+			reason: "accepted by and filter, no pattern matched",
+		},
+		{
+			name:   "Should explain an empty Or",
+			filter: filters.Or(),
+			path:   "main.go",
+			want:   filters.Explanation{Accepted: false, Filter: "or", Index: -1},
+			reason: "rejected by or filter, no pattern matched",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filters.Explain(tt.filter, tt.path, tt.data)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.reason, got.String())
+			accepted, err := filters.AcceptData(tt.filter, tt.path, tt.data)
+			assert.NoError(t, err)
+			assert.Equal(t, accepted, got.Accepted)
+		})
+	}
+}
+
+func TestExplain_Fail(t *testing.T) {
+	failing := &mockDataFilter{err: errors.New("some-error")}
+	condition, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "*.go", Condition: "{{ index .list 1 }}"})
+	assert.NoError(t, err)
+	tests := []struct {
+		name    string
+		filter  filters.Filter
+		wantErr string
+	}{
+		{name: "Should return the error of a custom filter", filter: failing, wantErr: "some-error"},
+		{name: "Should return the error of And", filter: filters.And(failing), wantErr: "some-error"},
+		{name: "Should return the error of Or", filter: filters.Or(failing), wantErr: "some-error"},
+		{name: "Should return the error of Not", filter: filters.Not(failing), wantErr: "some-error"},
+		{name: "Should return the error of a condition", filter: condition, wantErr: `unable to evaluate the condition of "*.go" for main.go: template: *.go:1:3: executing "*.go" at <index .list 1>: error calling index: index out of range: 1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filters.Explain(tt.filter, "main.go", map[string]interface{}{"list": []string{}})
+
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Equal(t, filters.Explanation{}, got)
+		})
+	}
+}
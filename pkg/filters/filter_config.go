@@ -0,0 +1,103 @@
+package filters
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FilterConfig is the configuration of a filter tree, that can be read from
+// YAML or JSON, i.e. from a manifest. Exactly one of the fields must be set.
+type FilterConfig struct {
+	Glob  []string       `yaml:"glob,omitempty" json:"glob,omitempty"`   // Glob patterns of an inclusive glob filter, see NewGlobFilter
+	Regex []string       `yaml:"regex,omitempty" json:"regex,omitempty"` // Regular expressions of an inclusive pattern filter, see NewPatternFilter
+	Expr  string         `yaml:"expr,omitempty" json:"expr,omitempty"`   // Filter expression, see ParseFilterExpression
+	And   []FilterConfig `yaml:"and,omitempty" json:"and,omitempty"`     // Filters that must all accept a path
+	Or    []FilterConfig `yaml:"or,omitempty" json:"or,omitempty"`       // Filters of which at least one must accept a path
+	Not   *FilterConfig  `yaml:"not,omitempty" json:"not,omitempty"`     // Filter that must not accept a path
+}
+
+// NewFilterFromConfig builds the filter tree described by the configuration,
+// or returns an error if a node of the tree is invalid
+func NewFilterFromConfig(config FilterConfig) (Filter, error) {
+	return config.build("filter")
+}
+
+// ParseFilterConfig builds a filter tree from its YAML or JSON configuration,
+// see FilterConfig, i.e.:
+//
+//	and:
+//	  - glob: ["src/**"]
+//	  - or:
+//	      - not: {glob: ["*_test.go"]}
+//	      - expr: "src/testutil/**"
+func ParseFilterConfig(data []byte) (Filter, error) {
+	config := FilterConfig{}
+	err := yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the filter configuration: %w", err)
+	}
+	return NewFilterFromConfig(config)
+}
+
+// build builds the filter of the node, path is the position of the node in
+// the tree, used in the errors
+func (c FilterConfig) build(path string) (Filter, error) {
+	set := 0
+	for _, isSet := range []bool{c.Glob != nil, c.Regex != nil, len(c.Expr) > 0, c.And != nil, c.Or != nil, c.Not != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("invalid %s: exactly one of glob, regex, expr, and, or, not must be set", path)
+	}
+
+	switch {
+	case c.And != nil:
+		children, err := buildChildren(c.And, path+".and")
+		if err != nil {
+			return nil, err
+		}
+		return And(children...), nil
+	case c.Or != nil:
+		children, err := buildChildren(c.Or, path+".or")
+		if err != nil {
+			return nil, err
+		}
+		return Or(children...), nil
+	case c.Not != nil:
+		filter, err := c.Not.build(path + ".not")
+		if err != nil {
+			return nil, err
+		}
+		return Not(filter), nil
+	}
+
+	var filter Filter
+	var err error
+	switch {
+	case c.Glob != nil:
+		filter, err = NewGlobFilter(true, c.Glob...)
+	case c.Regex != nil:
+		filter, err = NewPatternFilter(true, c.Regex...)
+	default:
+		filter, err = ParseFilterExpression(c.Expr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return filter, nil
+}
+
+func buildChildren(configs []FilterConfig, path string) ([]Filter, error) {
+	children := make([]Filter, 0, len(configs))
+	for i, config := range configs {
+		child, err := config.build(fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	return children, nil
+}
@@ -0,0 +1,101 @@
+package filters_test
+
+import (
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilterConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		accepted []string
+		rejected []string
+	}{
+		{
+			name: "Should build a nested tree from YAML",
+			config: `
+and:
+  - glob: ["src/**"]
+  - or:
+      - not: {glob: ["*_test.go"]}
+      - expr: "src/testutil/**"
+`,
+			accepted: []string{"src/main.go", "src/testutil/util_test.go"},
+			rejected: []string{"src/main_test.go", "README.md"},
+		},
+		{
+			name:     "Should build a tree from JSON",
+			config:   `{"or": [{"regex": ["\\.go$"]}, {"glob": ["*.md", "!CHANGELOG.md"]}]}`,
+			accepted: []string{"main.go", "README.md"},
+			rejected: []string{"CHANGELOG.md", "file.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := filters.ParseFilterConfig([]byte(tt.config))
+
+			assert.NoError(t, err)
+			for _, path := range tt.accepted {
+				assert.True(t, filter.Accept(path), path)
+			}
+			for _, path := range tt.rejected {
+				assert.False(t, filter.Accept(path), path)
+			}
+		})
+	}
+}
+
+func TestParseFilterConfig_Fail(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:    "Should return an error if the configuration is not valid YAML",
+			config:  "and: [",
+			wantErr: "unable to parse the filter configuration: yaml: line 1: did not find expected node content",
+		},
+		{
+			name:    "Should return an error if no field is set",
+			config:  "{}",
+			wantErr: "invalid filter: exactly one of glob, regex, expr, and, or, not must be set",
+		},
+		{
+			name:    "Should return an error if multiple fields are set",
+			config:  "or: [{glob: [a], regex: [b]}]",
+			wantErr: "invalid filter.or[0]: exactly one of glob, regex, expr, and, or, not must be set",
+		},
+		{
+			name:    "Should return an error with the path of an invalid pattern",
+			config:  "and: [{glob: [a]}, {not: {regex: ['[']}}]",
+			wantErr: "invalid filter.and[1].not: error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			name:    "Should return an error with the path of an invalid expression",
+			config:  "or: [{expr: 'a &&'}]",
+			wantErr: `invalid filter.or[0]: invalid filter expression "a &&": unexpected end of the expression`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := filters.ParseFilterConfig([]byte(tt.config))
+
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Nil(t, filter)
+		})
+	}
+}
+
+func TestNewFilterFromConfig(t *testing.T) {
+	filter, err := filters.NewFilterFromConfig(filters.FilterConfig{
+		Not: &filters.FilterConfig{Glob: []string{"*.go"}},
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, filter.Accept("main.go"))
+	assert.True(t, filter.Accept("README.md"))
+}
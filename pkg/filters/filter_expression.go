@@ -0,0 +1,224 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+)
+
+type expressionTokenKind int
+
+const (
+	tokenEnd expressionTokenKind = iota
+	tokenTerm
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+// expressionToken is a token of a filter expression
+type expressionToken struct {
+	kind    expressionTokenKind
+	pattern string // pattern of a term
+	regex   bool   // the term is a regular expression instead of a glob
+	pos     int    // position of the token in the expression
+}
+
+// ParseFilterExpression builds a filter from an expression, to be used for
+// instance in command line flags. The expression combines terms with "&&"
+// (And), "||" (Or), "!" (Not) and parenthesis; "!" has the highest precedence
+// and "||" the lowest. A term is a glob pattern (see NewGlobFilter), or a
+// regular expression if prefixed by "regex:", that accepts the matching
+// paths; patterns with spaces or operators can be quoted with double (that
+// support "\" escapes) or single quotes, i.e.:
+//
+//	src/** && !(*_test.go || regex:"^src/gen/")
+func ParseFilterExpression(expression string) (Filter, error) {
+	tokens, err := lexFilterExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression %q: %w", expression, err)
+	}
+
+	parser := &expressionParser{tokens: tokens}
+	filter, err := parser.parseOr()
+	if err == nil && parser.peek().kind != tokenEnd {
+		err = parser.unexpected()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression %q: %w", expression, err)
+	}
+	return filter, nil
+}
+
+// lexFilterExpression splits the expression in tokens
+func lexFilterExpression(expression string) ([]expressionToken, error) {
+	tokens := make([]expressionToken, 0)
+	for i := 0; i < len(expression); {
+		switch c := expression[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(expression[i:], "&&"):
+			tokens = append(tokens, expressionToken{kind: tokenAnd, pos: i})
+			i += 2
+		case strings.HasPrefix(expression[i:], "||"):
+			tokens = append(tokens, expressionToken{kind: tokenOr, pos: i})
+			i += 2
+		case c == '!':
+			tokens = append(tokens, expressionToken{kind: tokenNot, pos: i})
+			i++
+		case c == '(':
+			tokens = append(tokens, expressionToken{kind: tokenOpen, pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, expressionToken{kind: tokenClose, pos: i})
+			i++
+		default:
+			token, end, err := lexTerm(expression, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token)
+			i = end
+		}
+	}
+	return append(tokens, expressionToken{kind: tokenEnd, pos: len(expression)}), nil
+}
+
+// lexTerm reads the term that starts at index start, and returns it with the
+// index of its end
+func lexTerm(expression string, start int) (expressionToken, int, error) {
+	token := expressionToken{kind: tokenTerm, pos: start}
+	i := start
+	if strings.HasPrefix(expression[i:], "regex:") {
+		token.regex = true
+		i += len("regex:")
+	}
+
+	if i < len(expression) && (expression[i] == '"' || expression[i] == '\'') {
+		quote := expression[i]
+		var builder strings.Builder
+		for i++; i < len(expression); i++ {
+			c := expression[i]
+			if c == quote {
+				token.pattern = builder.String()
+				return token, i + 1, nil
+			}
+			if c == '\\' && quote == '"' && i+1 < len(expression) {
+				i++
+				c = expression[i]
+			}
+			builder.WriteByte(c)
+		}
+		return token, i, fmt.Errorf("unterminated string at position %d", start)
+	}
+
+	end := i
+	for end < len(expression) && !strings.ContainsRune(" \t\n\r()", rune(expression[end])) &&
+		!strings.HasPrefix(expression[end:], "&&") && !strings.HasPrefix(expression[end:], "||") {
+		end++
+	}
+	token.pattern = expression[i:end]
+	if len(token.pattern) == 0 {
+		return token, end, fmt.Errorf("empty pattern at position %d", start)
+	}
+	return token, end, nil
+}
+
+// expressionParser is a recursive descent parser of the filter expressions
+type expressionParser struct {
+	tokens []expressionToken
+	next   int
+}
+
+func (p *expressionParser) peek() expressionToken {
+	return p.tokens[p.next]
+}
+
+func (p *expressionParser) unexpected() error {
+	token := p.peek()
+	if token.kind == tokenEnd {
+		return fmt.Errorf("unexpected end of the expression")
+	}
+	return fmt.Errorf("unexpected token at position %d", token.pos)
+}
+
+func (p *expressionParser) parseOr() (Filter, error) {
+	filter, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	filters := []Filter{filter}
+	for p.peek().kind == tokenOr {
+		p.next++
+		filter, err = p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return Or(filters...), nil
+}
+
+func (p *expressionParser) parseAnd() (Filter, error) {
+	filter, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	filters := []Filter{filter}
+	for p.peek().kind == tokenAnd {
+		p.next++
+		filter, err = p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return And(filters...), nil
+}
+
+func (p *expressionParser) parseUnary() (Filter, error) {
+	token := p.peek()
+	switch token.kind {
+	case tokenNot:
+		p.next++
+		filter, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(filter), nil
+
+	case tokenOpen:
+		p.next++
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenClose {
+			return nil, p.unexpected()
+		}
+		p.next++
+		return filter, nil
+
+	case tokenTerm:
+		p.next++
+		var filter Filter
+		var err error
+		if token.regex {
+			filter, err = NewPatternFilter(true, token.pattern)
+		} else {
+			filter, err = NewGlobFilter(true, token.pattern)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern at position %d: %w", token.pos, err)
+		}
+		return filter, nil
+	}
+	return nil, p.unexpected()
+}
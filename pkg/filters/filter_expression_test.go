@@ -0,0 +1,143 @@
+package filters_test
+
+import (
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilterExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		accepted   []string
+		rejected   []string
+	}{
+		{
+			name:       "Should build a glob filter from a term",
+			expression: "src/**",
+			accepted:   []string{"src/main.go"},
+			rejected:   []string{"main.go"},
+		},
+		{
+			name:       "Should build a pattern filter from a regex term",
+			expression: `regex:\.go$`,
+			accepted:   []string{"src/main.go"},
+			rejected:   []string{"README.md"},
+		},
+		{
+			name:       "Should combine the terms",
+			expression: "src/** && !*_test.go || src/testutil/**",
+			accepted:   []string{"src/main.go", "src/testutil/util_test.go"},
+			rejected:   []string{"src/main_test.go", "README.md"},
+		},
+		{
+			name:       "Should give precedence to and over or",
+			expression: "a/** || b/** && *.go",
+			accepted:   []string{"a/file.txt", "b/main.go"},
+			rejected:   []string{"b/file.txt"},
+		},
+		{
+			name:       "Should group with parenthesis",
+			expression: "(a/** || b/**) && *.go",
+			accepted:   []string{"a/main.go", "b/main.go"},
+			rejected:   []string{"a/file.txt"},
+		},
+		{
+			name:       "Should apply the not to the next term only",
+			expression: "!*.go && src/**",
+			accepted:   []string{"src/file.txt"},
+			rejected:   []string{"src/main.go", "file.txt"},
+		},
+		{
+			name:       "Should allow multiple nots",
+			expression: "!!*.go",
+			accepted:   []string{"main.go"},
+			rejected:   []string{"file.txt"},
+		},
+		{
+			name:       "Should read double quoted patterns with escapes",
+			expression: `"my file\".txt" || regex:"^a b$"`,
+			accepted:   []string{`my file".txt`, "a b"},
+			rejected:   []string{"my", "file.txt"},
+		},
+		{
+			name:       "Should read single quoted patterns without escapes",
+			expression: `'a && "b\"' || c`,
+			accepted:   []string{`a && "b"`, "c"},
+			rejected:   []string{"a", `a && "b\"`},
+		},
+		{
+			name:       "Should allow an exclamation mark inside a pattern",
+			expression: "file[!0-9].txt",
+			accepted:   []string{"filea.txt"},
+			rejected:   []string{"file1.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := filters.ParseFilterExpression(tt.expression)
+
+			assert.NoError(t, err)
+			for _, path := range tt.accepted {
+				assert.True(t, filter.Accept(path), path)
+			}
+			for _, path := range tt.rejected {
+				assert.False(t, filter.Accept(path), path)
+			}
+		})
+	}
+}
+
+func TestParseFilterExpression_Fail(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{
+			name:       "Should return an error if the expression is empty",
+			expression: "",
+			wantErr:    `invalid filter expression "": unexpected end of the expression`,
+		},
+		{
+			name:       "Should return an error if an operand is missing",
+			expression: "a && || b",
+			wantErr:    `invalid filter expression "a && || b": unexpected token at position 5`,
+		},
+		{
+			name:       "Should return an error if a parenthesis is not closed",
+			expression: "(a || b",
+			wantErr:    `invalid filter expression "(a || b": unexpected end of the expression`,
+		},
+		{
+			name:       "Should return an error if a parenthesis is not opened",
+			expression: "a || b)",
+			wantErr:    `invalid filter expression "a || b)": unexpected token at position 6`,
+		},
+		{
+			name:       "Should return an error if a string is not terminated",
+			expression: `a || "b`,
+			wantErr:    `invalid filter expression "a || \"b": unterminated string at position 5`,
+		},
+		{
+			name:       "Should return an error if a regex pattern is empty",
+			expression: "regex:",
+			wantErr:    `invalid filter expression "regex:": empty pattern at position 0`,
+		},
+		{
+			name:       "Should return an error if a pattern is invalid",
+			expression: "a || regex:[",
+			wantErr:    "invalid filter expression \"a || regex:[\": invalid pattern at position 5: error parsing regexp: missing closing ]: `[`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := filters.ParseFilterExpression(tt.expression)
+
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Nil(t, filter)
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package filters
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// globRule is a pattern of a glob filter
+type globRule struct {
+	pattern  string // pattern as specified, without trailing spaces
+	index    int    // index of the pattern in the ones of the filter
+	regexp   *regexp.Regexp
+	negated  bool // the pattern starts with "!"
+	dirOnly  bool // the pattern ends with "/", so it only matches folders
+	anchored bool // the pattern contains a "/", so it matches the whole path instead of the name
+}
+
+type globFilter struct {
+	rules     []*globRule
+	inclusive bool
+}
+
+// NewGlobFilter returns a new instance of Filter configured with the specified
+// glob patterns, with the same semantics of .gitignore files:
+//   - "*" matches anything but "/", "?" any character but "/", and "[...]" a
+//     range of characters;
+//   - "**/" matches zero or more folders, and a trailing "/**" everything
+//     inside a folder;
+//   - a pattern that starts with "!" negates a previous one;
+//   - a pattern that ends with "/" only matches folders (and their files);
+//   - a pattern with a "/" at the beginning or in the middle is relative to
+//     the root, otherwise it matches the name at any level;
+//   - empty lines and lines that start with "#" are ignored, and "\" escapes
+//     the next character.
+//
+// The last pattern that matches a path decides if it matches, and, like git, a
+// file can't be negated if one of its folders matches. The filter accepts a
+// path if it is inclusive and the path matches, or if it is exclusive
+// (inclusive=false) and the path doesn't match. It returns an error if one of
+// the patterns is invalid.
+func NewGlobFilter(inclusive bool, patterns ...string) (Filter, error) {
+	rules := make([]*globRule, 0, len(patterns))
+	for i, pattern := range patterns {
+		rule, err := parseGlobRule(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if rule != nil {
+			rule.index = i
+			rules = append(rules, rule)
+		}
+	}
+
+	return &globFilter{
+		rules:     rules,
+		inclusive: inclusive,
+	}, nil
+}
+
+// parseGlobRule parses a line of a gitignore-like file, it returns nil if the
+// line is empty or a comment
+func parseGlobRule(line string) (*globRule, error) {
+	pattern := trimTrailingSpaces(strings.TrimSuffix(line, "\r"))
+	if len(pattern) == 0 || strings.HasPrefix(pattern, "#") {
+		return nil, nil
+	}
+
+	rule := &globRule{pattern: pattern}
+	if strings.HasPrefix(pattern, "!") {
+		rule.negated = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") && !strings.HasSuffix(pattern, "\\/") {
+		rule.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	rule.anchored = strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if len(pattern) == 0 {
+		return nil, nil
+	}
+
+	regex, err := regexp.Compile(globToRegexp(pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %q: %w", line, err)
+	}
+	rule.regexp = regex
+	return rule, nil
+}
+
+// trimTrailingSpaces removes the trailing spaces, unless they are escaped
+func trimTrailingSpaces(pattern string) string {
+	for strings.HasSuffix(pattern, " ") && !strings.HasSuffix(pattern, "\\ ") {
+		pattern = pattern[:len(pattern)-1]
+	}
+	return pattern
+}
+
+// globToRegexp converts the glob pattern to a regular expression that matches
+// the whole value
+func globToRegexp(pattern string) string {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			isComponent := (i == 0 || pattern[i-1] == '/') && i+1 < len(pattern) && pattern[i+1] == '*' &&
+				(i+2 == len(pattern) || pattern[i+2] == '/')
+			if !isComponent {
+				builder.WriteString("[^/]*")
+				continue
+			}
+			if i+2 == len(pattern) {
+				builder.WriteString(".*")
+			} else {
+				builder.WriteString("(?:.*/)?")
+				i++ // skip the slash
+			}
+			i++ // skip the second star
+
+		case '?':
+			builder.WriteString("[^/]")
+
+		case '[':
+			end := classEnd(pattern, i)
+			if end < 0 {
+				builder.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : end]
+			if strings.HasPrefix(class, "!") || strings.HasPrefix(class, "^") {
+				class = "^/" + class[1:]
+			}
+			builder.WriteString("[" + class + "]")
+			i = end
+
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			builder.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	builder.WriteString("$")
+	return builder.String()
+}
+
+// classEnd returns the index of the "]" that closes the range of characters
+// that starts at index start, or -1 if it is not closed
+func classEnd(pattern string, start int) int {
+	i := start + 1
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		i++
+	}
+	if i < len(pattern) && pattern[i] == ']' {
+		i++
+	}
+	for ; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// Accept returns true if it is inclusive and the value matches the patterns,
+// or if it is exclusive (inclusive=false) and the value doesn't match them.
+func (f *globFilter) Accept(value string) bool {
+	return f.matches(value) == f.inclusive
+}
+
+// Explain returns the pattern that decides whether the value matches, if any
+func (f *globFilter) Explain(value string, data map[string]interface{}) (Explanation, error) {
+	explanation := Explanation{
+		Accepted: f.Accept(value),
+		Filter:   "glob",
+		Mode:     filterMode(f.inclusive),
+		Index:    -1,
+	}
+	if rule := f.decidingRule(value); rule != nil {
+		explanation.Index = rule.index
+		explanation.Pattern = rule.pattern
+	}
+	return explanation, nil
+}
+
+// matches returns whether the path, or one of its folders, matches the
+// patterns
+func (f *globFilter) matches(path string) bool {
+	rule := f.decidingRule(path)
+	return rule != nil && !rule.negated
+}
+
+// decidingRule returns the rule that decides whether the path matches: the
+// last one that matches one of its folders, if not negated, or the last one
+// that matches the path. It returns nil if no rule matches.
+func (f *globFilter) decidingRule(path string) *globRule {
+	components := strings.Split(strings.Trim(filepath.ToSlash(path), "/"), "/")
+	for i := 1; i < len(components); i++ {
+		if rule := f.lastMatchingRule(components[:i], true); rule != nil && !rule.negated {
+			return rule
+		}
+	}
+	return f.lastMatchingRule(components, false)
+}
+
+// lastMatchingRule returns the last rule that matches the path, or nil
+func (f *globFilter) lastMatchingRule(components []string, isDir bool) *globRule {
+	path := strings.Join(components, "/")
+	name := components[len(components)-1]
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		rule := f.rules[i]
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		value := name
+		if rule.anchored {
+			value = path
+		}
+		if rule.regexp.MatchString(value) {
+			return rule
+		}
+	}
+	return nil
+}
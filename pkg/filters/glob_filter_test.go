@@ -0,0 +1,69 @@
+package filters_test
+
+import (
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGlobFilter_Fail_ShouldReturnErrorIfAPatternIsInvalid(t *testing.T) {
+	filter, err := filters.NewGlobFilter(false, "*.txt", "[z-a].txt")
+
+	assert.Equal(t, "invalid glob pattern \"[z-a].txt\": error parsing regexp: invalid character class range: `z-a`", err.Error())
+	assert.Nil(t, filter)
+}
+
+func Test_globFilter_Accept(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		want     bool
+	}{
+		{name: "Should match the name at any level", patterns: []string{"*.log"}, path: "a/b/c.log", want: true},
+		{name: "Should not match a different extension", patterns: []string{"*.log"}, path: "a/b/c.txt", want: false},
+		{name: "Should not match a slash with a star", patterns: []string{"a/*.txt"}, path: "a/b/c.txt", want: false},
+		{name: "Should match a single character", patterns: []string{"file?.txt"}, path: "file1.txt", want: true},
+		{name: "Should not match a slash with a question mark", patterns: []string{"a?b"}, path: "a/b", want: false},
+		{name: "Should match a range", patterns: []string{"file[0-9].txt"}, path: "file5.txt", want: true},
+		{name: "Should not match a negated range", patterns: []string{"file[!0-9].txt"}, path: "file5.txt", want: false},
+		{name: "Should match a name at any level with a leading double star", patterns: []string{"**/main.go"}, path: "cmd/app/main.go", want: true},
+		{name: "Should match a name in the root with a leading double star", patterns: []string{"**/main.go"}, path: "main.go", want: true},
+		{name: "Should match zero folders with a middle double star", patterns: []string{"a/**/b"}, path: "a/b", want: true},
+		{name: "Should match many folders with a middle double star", patterns: []string{"a/**/b"}, path: "a/x/y/b", want: true},
+		{name: "Should match everything inside a folder with a trailing double star", patterns: []string{"a/**"}, path: "a/x/y", want: true},
+		{name: "Should not match the folder itself with a trailing double star", patterns: []string{"a/**"}, path: "a", want: false},
+		{name: "Should treat a double star in a name as a star", patterns: []string{"a**.txt"}, path: "ab.txt", want: true},
+		{name: "Should anchor a pattern with a leading slash", patterns: []string{"/main.go"}, path: "cmd/main.go", want: false},
+		{name: "Should match an anchored pattern in the root", patterns: []string{"/main.go"}, path: "main.go", want: true},
+		{name: "Should anchor a pattern with a middle slash", patterns: []string{"cmd/main.go"}, path: "x/cmd/main.go", want: false},
+		{name: "Should match the files of a matching folder", patterns: []string{"build"}, path: "a/build/out.txt", want: true},
+		{name: "Should match the files of a folder only pattern", patterns: []string{"build/"}, path: "build/out.txt", want: true},
+		{name: "Should not match a file with a folder only pattern", patterns: []string{"build/"}, path: "a/build", want: false},
+		{name: "Should negate a previous pattern", patterns: []string{"*.txt", "!keep.txt"}, path: "keep.txt", want: false},
+		{name: "Should use the last matching pattern", patterns: []string{"!keep.txt", "*.txt"}, path: "keep.txt", want: true},
+		{name: "Should not negate a file of a matching folder", patterns: []string{"build/", "!build/keep.txt"}, path: "build/keep.txt", want: true},
+		{name: "Should negate a file of the contents of a folder", patterns: []string{"build/**", "!build/keep.txt"}, path: "build/keep.txt", want: false},
+		{name: "Should negate a folder", patterns: []string{"/*", "!/src"}, path: "src/main.go", want: false},
+		{name: "Should ignore comments", patterns: []string{"# main.go"}, path: "# main.go", want: false},
+		{name: "Should match an escaped hash", patterns: []string{`\#main.go`}, path: "#main.go", want: true},
+		{name: "Should match an escaped exclamation mark", patterns: []string{`\!main.go`}, path: "!main.go", want: true},
+		{name: "Should match an escaped star", patterns: []string{`\*.go`}, path: "main.go", want: false},
+		{name: "Should ignore the trailing spaces", patterns: []string{"main.go  "}, path: "main.go", want: true},
+		{name: "Should keep the escaped trailing spaces", patterns: []string{`main.go\ `}, path: "main.go ", want: true},
+		{name: "Should ignore the carriage return", patterns: []string{"main.go\r"}, path: "main.go", want: true},
+		{name: "Should quote the regexp characters", patterns: []string{"a+b.go"}, path: "aab.go", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inclusive, err := filters.NewGlobFilter(true, tt.patterns...)
+			assert.NoError(t, err)
+			exclusive, err := filters.NewGlobFilter(false, tt.patterns...)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want, inclusive.Accept(tt.path))
+			assert.Equal(t, !tt.want, exclusive.Accept(tt.path))
+		})
+	}
+}
@@ -9,3 +9,7 @@ func NewNoOpFilter() Filter {
 func (f *noOpFilter) Accept(value string) bool {
 	return true
 }
+
+func (f *noOpFilter) Explain(value string, data map[string]interface{}) (Explanation, error) {
+	return Explanation{Accepted: true, Filter: "noop", Index: -1}, nil
+}
@@ -0,0 +1,37 @@
+package filters
+
+type notFilter struct {
+	filter Filter
+}
+
+// Not returns a new Filter that negates the input one (it returns true if the
+// filter doesn't accept the value). The data is passed to the filter if it
+// depends on it, see DataFilter.
+func Not(filter Filter) Filter {
+	return &notFilter{
+		filter: filter,
+	}
+}
+
+func (f *notFilter) Accept(value string) bool {
+	return !f.filter.Accept(value)
+}
+
+func (f *notFilter) AcceptData(value string, data map[string]interface{}) (bool, error) {
+	accepted, err := AcceptData(f.filter, value, data)
+	if err != nil {
+		return false, err
+	}
+	return !accepted, nil
+}
+
+// Explain returns the explanation of the negated filter, with the opposite
+// outcome
+func (f *notFilter) Explain(value string, data map[string]interface{}) (Explanation, error) {
+	explanation, err := Explain(f.filter, value, data)
+	if err != nil {
+		return Explanation{}, err
+	}
+	explanation.Accepted = !explanation.Accepted
+	return explanation.under("not"), nil
+}
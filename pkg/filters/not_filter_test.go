@@ -0,0 +1,16 @@
+package filters_test
+
+import (
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_notFilter_Accept_ShouldReturnFalseIfTheFilterAcceptsTheFile(t *testing.T) {
+	assert.False(t, filters.Not(&mockFilter{"file-to-match"}).Accept("file-to-match"))
+}
+
+func Test_notFilter_Accept_ShouldReturnTrueIfTheFilterDoesNotAcceptTheFile(t *testing.T) {
+	assert.True(t, filters.Not(&mockFilter{"file-to-exclude"}).Accept("file-to-match"))
+}
@@ -1,11 +1,14 @@
 package filters
 
+import "fmt"
+
 type orFilter struct {
 	filters []Filter
 }
 
 // Or returns a new Filter that merges the input ones, it performs a logical OR
-// (if there is one that matches the value it will return true)
+// (if there is one that matches the value it will return true). The data is
+// passed to the filters that depend on it, see DataFilter.
 func Or(filters ...Filter) Filter {
 	return &orFilter{
 		filters: filters,
@@ -20,3 +23,34 @@ func (f *orFilter) Accept(value string) bool {
 	}
 	return false
 }
+
+func (f *orFilter) AcceptData(value string, data map[string]interface{}) (bool, error) {
+	for _, filter := range f.filters {
+		accepted, err := AcceptData(filter, value, data)
+		if err != nil {
+			return false, err
+		}
+		if accepted {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Explain returns the explanation of the first filter that accepts the value,
+// or of the last one if none of them accepts it
+func (f *orFilter) Explain(value string, data map[string]interface{}) (Explanation, error) {
+	explanation := Explanation{Accepted: false, Filter: "or", Index: -1}
+	for i, filter := range f.filters {
+		var err error
+		explanation, err = Explain(filter, value, data)
+		if err != nil {
+			return Explanation{}, err
+		}
+		explanation = explanation.under(fmt.Sprintf("or[%d]", i))
+		if explanation.Accepted {
+			break
+		}
+	}
+	return explanation, nil
+}
@@ -32,12 +32,27 @@ func NewPatternFilter(inclusive bool, patterns ...string) (Filter, error) {
 }
 
 // NewPatternFilterFromInstance duplicates the filter, using the same pattern(s)
-// and the specified inclusive flag
+// and the specified inclusive flag. Filters other than the pattern and glob
+// ones have no patterns, so they are considered inclusive: the function
+// returns the filter itself if inclusive is true, its negation otherwise.
 func NewPatternFilterFromInstance(f Filter, inclusive bool) Filter {
-	return &patternFilter{
-		patterns:  f.(*patternFilter).patterns,
-		inclusive: inclusive,
+	switch filter := f.(type) {
+	case *patternFilter:
+		return &patternFilter{
+			patterns:  filter.patterns,
+			inclusive: inclusive,
+		}
+	case *globFilter:
+		return &globFilter{
+			rules:     filter.rules,
+			inclusive: inclusive,
+		}
+	}
+
+	if inclusive {
+		return f
 	}
+	return Not(f)
 }
 
 // Accept returns true if it is inclusive and the value matches one of the
@@ -60,3 +75,23 @@ func (f *patternFilter) Accept(value string) bool {
 	}
 	return !valueWhenFound
 }
+
+// Explain returns the index of the first regexp that matches the value, if
+// any
+func (f *patternFilter) Explain(value string, data map[string]interface{}) (Explanation, error) {
+	explanation := Explanation{
+		Accepted: !f.inclusive,
+		Filter:   "pattern",
+		Mode:     filterMode(f.inclusive),
+		Index:    -1,
+	}
+	for i, pattern := range f.patterns {
+		if pattern.MatchString(value) {
+			explanation.Accepted = f.inclusive
+			explanation.Index = i
+			explanation.Pattern = pattern.String()
+			break
+		}
+	}
+	return explanation, nil
+}
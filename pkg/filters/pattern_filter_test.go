@@ -79,3 +79,20 @@ func Test_PatternFilter_NewInstance_ShouldOverwriteConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_PatternFilter_NewInstance_ShouldOverwriteConfigOfGlobFilters(t *testing.T) {
+	f, err := filters.NewGlobFilter(false, "*.go")
+	assert.Nil(t, err)
+
+	assert.True(t, filters.NewPatternFilterFromInstance(f, true).Accept("main.go"))
+	assert.False(t, filters.NewPatternFilterFromInstance(f, true).Accept("README.md"))
+	assert.False(t, filters.NewPatternFilterFromInstance(f, false).Accept("main.go"))
+}
+
+func Test_PatternFilter_NewInstance_ShouldNegateOtherFiltersIfExclusive(t *testing.T) {
+	f := &mockFilter{"file-to-match"}
+
+	assert.Equal(t, f, filters.NewPatternFilterFromInstance(f, true))
+	assert.False(t, filters.NewPatternFilterFromInstance(f, false).Accept("file-to-match"))
+	assert.True(t, filters.NewPatternFilterFromInstance(f, false).Accept("other-file"))
+}
@@ -0,0 +1,30 @@
+package filters
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ScaffoldIgnoreFile is the name of the file, in the root of the templates
+// folder, with the glob patterns of the templates to ignore
+const ScaffoldIgnoreFile = ".scaffoldignore"
+
+// LoadScaffoldIgnore reads the .scaffoldignore file in the root of the
+// templates folder, and returns an exclusive glob filter (see NewGlobFilter)
+// with its patterns, that also excludes the file itself. If the file doesn't
+// exist the filter only excludes it.
+func LoadScaffoldIgnore(templatesDir string) (Filter, error) {
+	patterns := []string{"/" + ScaffoldIgnoreFile}
+
+	content, err := os.ReadFile(filepath.Join(templatesDir, ScaffoldIgnoreFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		patterns = append(patterns, strings.Split(string(content), "\n")...)
+	}
+
+	return NewGlobFilter(false, patterns...)
+}
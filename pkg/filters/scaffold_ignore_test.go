@@ -0,0 +1,72 @@
+package filters_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadScaffoldIgnore(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		accepted []string
+		rejected []string
+		wantErr  string
+	}{
+		{
+			name:     "Should exclude the patterns of the file",
+			content:  "# generated files\n*.log\n\nbuild/\n!important.log\n",
+			accepted: []string{"main.go", "important.log", "src/.scaffoldignore"},
+			rejected: []string{".scaffoldignore", "debug.log", "build/main.go"},
+		},
+		{
+			name:     "Should only exclude the file itself if it doesn't exist",
+			accepted: []string{"main.go", "debug.log"},
+			rejected: []string{".scaffoldignore"},
+		},
+		{
+			name:    "Should return an error if a pattern is invalid",
+			content: "[z-a]\n",
+			wantErr: "invalid glob pattern \"[z-a]\": error parsing regexp: invalid character class range: `z-a`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if len(tt.content) > 0 {
+				err := os.WriteFile(filepath.Join(dir, filters.ScaffoldIgnoreFile), []byte(tt.content), 0644)
+				assert.NoError(t, err)
+			}
+
+			filter, err := filters.LoadScaffoldIgnore(dir)
+
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, filter)
+				return
+			}
+			assert.NoError(t, err)
+			for _, path := range tt.accepted {
+				assert.True(t, filter.Accept(path), path)
+			}
+			for _, path := range tt.rejected {
+				assert.False(t, filter.Accept(path), path)
+			}
+		})
+	}
+}
+
+func TestLoadScaffoldIgnore_Fail_ShouldReturnErrorIfTheFileCannotBeRead(t *testing.T) {
+	dir := t.TempDir()
+	err := os.Mkdir(filepath.Join(dir, filters.ScaffoldIgnoreFile), os.ModePerm)
+	assert.NoError(t, err)
+
+	filter, err := filters.LoadScaffoldIgnore(dir)
+
+	assert.Error(t, err)
+	assert.Nil(t, filter)
+}
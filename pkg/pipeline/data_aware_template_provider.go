@@ -0,0 +1,21 @@
+package pipeline
+
+// DataAwareTemplateProvider is implemented by template providers that depend
+// on the data of the run, i.e. to filter the templates with a
+// filters.DataFilter. The pipeline sets the data, after the preprocessors,
+// on the provider of each run before reading its templates.
+type DataAwareTemplateProvider interface {
+	TemplateProvider
+
+	// SetData sets the data of the run, it is called before the first call to
+	// NextTemplate.
+	SetData(data map[string]interface{})
+}
+
+// SetTemplateProviderData sets the data on the provider, if it is a
+// DataAwareTemplateProvider
+func SetTemplateProviderData(provider TemplateProvider, data map[string]interface{}) {
+	if dataAwareProvider, ok := provider.(DataAwareTemplateProvider); ok {
+		dataAwareProvider.SetData(data)
+	}
+}
@@ -0,0 +1,18 @@
+package pipeline
+
+import "context"
+
+type dataContextKey struct{}
+
+// ContextWithData returns a copy of ctx that carries the data of the run
+func ContextWithData(ctx context.Context, data map[string]interface{}) context.Context {
+	return context.WithValue(ctx, dataContextKey{}, data)
+}
+
+// DataFromContext returns the data of the run carried by ctx, or nil if not
+// set. The pipeline sets the data, after the preprocessors, in the context of
+// the templates passed to the collectors (see Template.Context).
+func DataFromContext(ctx context.Context) map[string]interface{} {
+	data, _ := ctx.Value(dataContextKey{}).(map[string]interface{})
+	return data
+}
@@ -0,0 +1,15 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataFromContext(t *testing.T) {
+	data := map[string]interface{}{"key": "value"}
+
+	assert.Equal(t, data, DataFromContext(ContextWithData(context.Background(), data)))
+	assert.Nil(t, DataFromContext(context.Background()))
+}
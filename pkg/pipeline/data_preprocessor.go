@@ -1,3 +1,35 @@
 package pipeline
 
+import "fmt"
+
 type DataPreprocessor func(map[string]interface{}) (map[string]interface{}, error)
+
+// namedDataPreprocessor is a preprocessor in the chain of the pipeline, the
+// name is optional and used only to report errors
+type namedDataPreprocessor struct {
+	name string
+	fn   DataPreprocessor
+}
+
+// DataPreprocessorError is returned when a preprocessor in the chain fails
+type DataPreprocessorError struct {
+	// Index is the position of the preprocessor in the chain, starting from 0
+	Index int
+
+	// Name is the name of the preprocessor, if specified (see
+	// PipelineBuilder.WithNamedDataPreprocessor)
+	Name string
+
+	Err error
+}
+
+func (e *DataPreprocessorError) Error() string {
+	if len(e.Name) > 0 {
+		return fmt.Sprintf("data preprocessor %q (index %d) failed: %s", e.Name, e.Index, e.Err.Error())
+	}
+	return fmt.Sprintf("data preprocessor at index %d failed: %s", e.Index, e.Err.Error())
+}
+
+func (e *DataPreprocessorError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,95 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func appendPreprocessor(value string) DataPreprocessor {
+	return func(data map[string]interface{}) (map[string]interface{}, error) {
+		out := map[string]interface{}{"steps": append(append([]string{}, data["steps"].([]string)...), value)}
+		return out, nil
+	}
+}
+
+func failingPreprocessor(data map[string]interface{}) (map[string]interface{}, error) {
+	return nil, errors.New("some-error")
+}
+
+func Test_pipeline_preprocessData(t *testing.T) {
+	tests := []struct {
+		name          string
+		preprocessors func(b *pipelineBuilder) *pipelineBuilder
+		want          map[string]interface{}
+		wantErr       error
+	}{
+		{
+			name:          "Should return data as is without preprocessors",
+			preprocessors: func(b *pipelineBuilder) *pipelineBuilder { return b },
+			want:          map[string]interface{}{"steps": []string{}},
+		},
+		{
+			name: "Should apply preprocessors in order",
+			preprocessors: func(b *pipelineBuilder) *pipelineBuilder {
+				return b.
+					WithDataPreprocessor(appendPreprocessor("first")).
+					WithNamedDataPreprocessor("second", appendPreprocessor("second")).
+					WithDataPreprocessor(nil).
+					WithDataPreprocessor(appendPreprocessor("third"))
+			},
+			want: map[string]interface{}{"steps": []string{"first", "second", "third"}},
+		},
+		{
+			name: "Should identify failing preprocessor by index",
+			preprocessors: func(b *pipelineBuilder) *pipelineBuilder {
+				return b.
+					WithDataPreprocessor(appendPreprocessor("first")).
+					WithDataPreprocessor(failingPreprocessor).
+					WithDataPreprocessor(appendPreprocessor("third"))
+			},
+			wantErr: errors.New("data preprocessor at index 1 failed: some-error"),
+		},
+		{
+			name: "Should identify failing preprocessor by name",
+			preprocessors: func(b *pipelineBuilder) *pipelineBuilder {
+				return b.
+					WithDataPreprocessor(appendPreprocessor("first")).
+					WithNamedDataPreprocessor("secrets", failingPreprocessor)
+			},
+			wantErr: errors.New("data preprocessor \"secrets\" (index 1) failed: some-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.preprocessors(NewPipelineBuilder().(*pipelineBuilder))
+
+			got, err := b.p.preprocessData(context.Background(), map[string]interface{}{"steps": []string{}})
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_pipeline_preprocessData_ShouldWrapError(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	p := NewPipelineBuilder().WithNamedDataPreprocessor("some-name", failingPreprocessor).p
+
+	_, err := p.preprocessData(tracing.ContextWithTracer(context.Background(), recorder), nil)
+
+	var preprocessorErr *DataPreprocessorError
+	assert.ErrorAs(t, err, &preprocessorErr)
+	assert.Equal(t, 0, preprocessorErr.Index)
+	assert.Equal(t, "some-name", preprocessorErr.Name)
+	assert.Equal(t, errors.New("some-error"), errors.Unwrap(err))
+	spans := recorder.SpansNamed("pipeline.preprocessor")
+	assert.Len(t, spans, 1)
+	name, _ := spans[0].Attribute("name")
+	assert.Equal(t, "some-name", name)
+	assert.Equal(t, errors.New("some-error"), spans[0].Err)
+}
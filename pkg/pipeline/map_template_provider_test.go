@@ -0,0 +1,51 @@
+package pipeline
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// mapTemplateProvider is a reusable provider of the templates in a map, sorted
+// by path
+type mapTemplateProvider struct {
+	contents map[string]string
+	paths    []string
+}
+
+func newMapTemplateProvider(contents map[string]string) *mapTemplateProvider {
+	paths := make([]string, 0, len(contents))
+	for path := range contents {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return &mapTemplateProvider{contents: contents, paths: paths}
+}
+
+func (p *mapTemplateProvider) NextTemplate() (*Template, error) {
+	if len(p.paths) == 0 {
+		return nil, io.EOF
+	}
+	path := p.paths[0]
+	p.paths = p.paths[1:]
+	return &Template{
+		Name:   path,
+		Path:   path,
+		Reader: io.NopCloser(strings.NewReader(p.contents[path])),
+	}, nil
+}
+
+func (p *mapTemplateProvider) Reopen() (TemplateProvider, error) {
+	return newMapTemplateProvider(p.contents), nil
+}
+
+type discardCollector struct{}
+
+func (c *discardCollector) Collect(args *Template) error {
+	_, err := io.Copy(io.Discard, args.Reader)
+	return err
+}
+
+func (c *discardCollector) OnPipelineCompleted() error {
+	return nil
+}
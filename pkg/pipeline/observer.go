@@ -0,0 +1,93 @@
+package pipeline
+
+// Observer is notified of the events of the pipeline runs, i.e. to log them,
+// collect metrics or show the progress. Observers are shared by all runs, so
+// they should be safe for concurrent use if the pipeline is processed
+// concurrently. Implementations can embed NoOpObserver to handle only some of
+// the events.
+type Observer interface {
+
+	// OnRunStarted is invoked when a run starts
+	OnRunStarted()
+
+	// OnCommonTemplateLoaded is invoked when a common template is loaded,
+	// after it has been parsed; if one fails to parse, it is invoked only for
+	// the ones that precede it
+	OnCommonTemplateLoaded(name string)
+
+	// OnTemplateStarted is invoked when the processing of a main template
+	// starts
+	OnTemplateStarted(path string)
+
+	// OnTemplateRendered is invoked when the rendering of a main template
+	// finishes successfully
+	OnTemplateRendered(path string, bytes int64)
+
+	// OnTemplateFailed is invoked when a main template cannot be parsed,
+	// rendered or collected
+	OnTemplateFailed(path string, err error)
+
+	// OnTemplateCollected is invoked when a main template has been collected
+	OnTemplateCollected(result TemplateResult)
+
+	// OnRunCompleted is invoked at the end of a run, with its summary and the
+	// error that caused it to fail, if any
+	OnRunCompleted(result *Result, err error)
+}
+
+// NoOpObserver is an Observer that ignores all the events, it can be embedded
+// by observers interested only in some of them.
+type NoOpObserver struct{}
+
+func (NoOpObserver) OnRunStarted()                               {}
+func (NoOpObserver) OnCommonTemplateLoaded(name string)          {}
+func (NoOpObserver) OnTemplateStarted(path string)               {}
+func (NoOpObserver) OnTemplateRendered(path string, bytes int64) {}
+func (NoOpObserver) OnTemplateFailed(path string, err error)     {}
+func (NoOpObserver) OnTemplateCollected(result TemplateResult)   {}
+func (NoOpObserver) OnRunCompleted(result *Result, err error)    {}
+
+// observers notifies the events to all the observers in the list, in order
+type observers []Observer
+
+func (o observers) OnRunStarted() {
+	for _, observer := range o {
+		observer.OnRunStarted()
+	}
+}
+
+func (o observers) OnCommonTemplateLoaded(name string) {
+	for _, observer := range o {
+		observer.OnCommonTemplateLoaded(name)
+	}
+}
+
+func (o observers) OnTemplateStarted(path string) {
+	for _, observer := range o {
+		observer.OnTemplateStarted(path)
+	}
+}
+
+func (o observers) OnTemplateRendered(path string, bytes int64) {
+	for _, observer := range o {
+		observer.OnTemplateRendered(path, bytes)
+	}
+}
+
+func (o observers) OnTemplateFailed(path string, err error) {
+	for _, observer := range o {
+		observer.OnTemplateFailed(path, err)
+	}
+}
+
+func (o observers) OnTemplateCollected(result TemplateResult) {
+	for _, observer := range o {
+		observer.OnTemplateCollected(result)
+	}
+}
+
+func (o observers) OnRunCompleted(result *Result, err error) {
+	for _, observer := range o {
+		observer.OnRunCompleted(result, err)
+	}
+}
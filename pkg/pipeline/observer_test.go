@@ -0,0 +1,176 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingObserver records the events it is notified of
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) OnRunStarted() {
+	o.events = append(o.events, "run-started")
+}
+
+func (o *recordingObserver) OnCommonTemplateLoaded(name string) {
+	o.events = append(o.events, "common-loaded:"+name)
+}
+
+func (o *recordingObserver) OnTemplateStarted(path string) {
+	o.events = append(o.events, "started:"+path)
+}
+
+func (o *recordingObserver) OnTemplateRendered(path string, bytes int64) {
+	o.events = append(o.events, fmt.Sprintf("rendered:%s:%d", path, bytes))
+}
+
+func (o *recordingObserver) OnTemplateFailed(path string, err error) {
+	o.events = append(o.events, "failed:"+path)
+}
+
+func (o *recordingObserver) OnTemplateCollected(result TemplateResult) {
+	o.events = append(o.events, fmt.Sprintf("collected:%s:%d", result.Path, result.Bytes))
+}
+
+func (o *recordingObserver) OnRunCompleted(result *Result, err error) {
+	o.events = append(o.events, fmt.Sprintf("run-completed:%d:%v", len(result.Templates), err))
+}
+
+// failingCollector fails to collect the template with the specified path
+type failingCollector struct {
+	discardCollector
+	path string
+}
+
+func (c *failingCollector) Collect(args *Template) error {
+	if args.Path == c.path {
+		return errors.New("some-collect-error")
+	}
+	return c.discardCollector.Collect(args)
+}
+
+// counterObserver embeds NoOpObserver to count only the started runs
+type counterObserver struct {
+	NoOpObserver
+	runs int
+}
+
+func (o *counterObserver) OnRunStarted() {
+	o.runs++
+}
+
+func Test_pipeline_Process_observers(t *testing.T) {
+	tests := []struct {
+		name       string
+		templates  map[string]string
+		common     map[string]string
+		collector  Collector
+		wantEvents []string
+	}{
+		{
+			name:      "Should notify events of a successful run",
+			templates: map[string]string{"file1": "some-content", "file2": `{{ template "common" }}`},
+			common:    map[string]string{"common": "abc"},
+			collector: &discardCollector{},
+			wantEvents: []string{
+				"run-started",
+				"common-loaded:common",
+				"started:file1",
+				"rendered:file1:12",
+				"collected:file1:12",
+				"started:file2",
+				"rendered:file2:3",
+				"collected:file2:3",
+				"run-completed:2:<nil>",
+			},
+		},
+		{
+			name:      "Should notify common templates loaded before one fails to parse",
+			templates: map[string]string{"file1": "some-content"},
+			common:    map[string]string{"common1": "abc", "common2": "{{ .Values.name }", "common3": "def"},
+			collector: &discardCollector{},
+			wantEvents: []string{
+				"run-started",
+				"common-loaded:common1",
+				"run-completed:0:template: common2:1: unexpected \"}\" in operand",
+			},
+		},
+		{
+			name:      "Should notify template that fails to parse",
+			templates: map[string]string{"file1": "{{ .Values.name }"},
+			collector: &discardCollector{},
+			wantEvents: []string{
+				"run-started",
+				"started:file1",
+				"failed:file1",
+				"run-completed:1:template: file1:1: unexpected \"}\" in operand",
+			},
+		},
+		{
+			name:      "Should notify template that fails to render",
+			templates: map[string]string{"file1": `{{ template "missing" }}`},
+			collector: &discardCollector{},
+			wantEvents: []string{
+				"run-started",
+				"started:file1",
+				"failed:file1",
+				"run-completed:1:template: file1:1:12: executing \"file1\" at <{{template \"missing\"}}>: template \"missing\" not defined",
+			},
+		},
+		{
+			name:      "Should notify template that fails to be collected",
+			templates: map[string]string{"file1": "some-content"},
+			collector: &failingCollector{path: "file1"},
+			wantEvents: []string{
+				"run-started",
+				"started:file1",
+				"rendered:file1:12",
+				"failed:file1",
+				"run-completed:1:some-collect-error",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := &recordingObserver{}
+			second := &recordingObserver{}
+			builder := NewPipelineBuilder().
+				WithFunctions(map[string]any{"dummy": func() string { return "" }}).
+				WithTemplateProvider(newMapTemplateProvider(tt.templates)).
+				WithCollector(tt.collector).
+				WithObserver(first).
+				WithObserver(second)
+			if tt.common != nil {
+				builder = builder.WithNamedTemplatesProvider(newMapTemplateProvider(tt.common))
+			}
+			p, err := builder.Build()
+			assert.NoError(t, err)
+
+			p.Process(map[string]interface{}{})
+
+			assert.Equal(t, tt.wantEvents, first.events)
+			assert.Equal(t, tt.wantEvents, second.events)
+		})
+	}
+}
+
+func TestNoOpObserver(t *testing.T) {
+	observer := &counterObserver{}
+	p, err := NewPipelineBuilder().
+		WithFunctions(map[string]any{"dummy": func() string { return "" }}).
+		WithTemplateProvider(newMapTemplateProvider(map[string]string{"file1": "some-content"})).
+		WithCollector(&discardCollector{}).
+		WithObserver(observer).
+		Build()
+	assert.NoError(t, err)
+
+	assert.NoError(t, p.Process(nil))
+	assert.NoError(t, p.Process(nil))
+
+	assert.Equal(t, 2, observer.runs)
+}
@@ -1,94 +1,355 @@
 package pipeline
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log/slog"
 	"text/template"
+	"time"
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/templates"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 )
 
 var _processNextTemplate = processNextTemplate
 
 type Pipeline interface {
+	// Process processes all the templates with the specified data. A pipeline
+	// can be processed multiple times, also concurrently, as long as its
+	// template providers are reusable (see ReusableTemplateProvider); the
+	// collectors are copied at each run if they are reusable (see
+	// ReusableCollector), otherwise they are shared by all runs.
+	//
+	// Templates are processed one at a time, in the order returned by the
+	// template provider, and passed to the collectors in the same order; the
+	// providers of the templateproviders package return them sorted by path
+	// (tar.gz streams only with the Sort option), so that logs, outputs and
+	// errors are the same on all machines.
 	Process(processData map[string]interface{}) error
+
+	// ProcessWithResult processes all the templates like Process, and returns
+	// a summary of the run, with the outcome of each template and of the files
+	// reported by the collectors. If an error occurs, the returned result
+	// contains the outcomes up to the failure.
+	ProcessWithResult(processData map[string]interface{}) (*Result, error)
+
+	// ProcessContext processes all the templates like ProcessWithResult, in
+	// the specified context. The stages of the run are traced as children of
+	// the span in ctx, if any, using the tracer of the pipeline (see
+	// PipelineBuilder.WithTracer), or the one in ctx (see
+	// tracing.ContextWithTracer); the context is propagated to the collectors
+	// through Template.Context, with the preprocessed data (see
+	// DataFromContext).
+	ProcessContext(ctx context.Context, processData map[string]interface{}) (*Result, error)
 }
 
 type pipeline struct {
-	dataPreprocessor       DataPreprocessor
+	dataPreprocessors      []namedDataPreprocessor
 	functions              template.FuncMap
 	templateAwareFns       templates.TemplateAwareFuncMap
 	collector              Collector
 	templateProvider       TemplateProvider
 	namedTemplatesProvider TemplateProvider
+	templateCache          TemplateCache
+	observers              observers
+	logger                 *slog.Logger
+	tracer                 tracing.Tracer
+}
+
+// log returns the logger of the pipeline, or the default one if not set
+func (p *pipeline) log() *slog.Logger {
+	if p.logger == nil {
+		return slog.Default()
+	}
+	return p.logger
 }
 
 // loadCommonTemplates loads all common templates into a base template that can be
-// reused across all main templates in the pipeline.
-func (p *pipeline) loadCommonTemplates() (*template.Template, error) {
+// reused across all main templates in the pipeline. It also returns the key that
+// identifies the content of the common templates in the cache, if any.
+func (p *pipeline) loadCommonTemplates(ctx context.Context) (_ *template.Template, _ string, err error) {
 	if p.namedTemplatesProvider == nil {
-		return nil, nil
+		return nil, "", nil
 	}
 
-	baseTemplate := template.New("").Funcs(p.functions)
+	_, span := tracing.Start(ctx, "pipeline.loadCommonTemplates")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
 
+	namedTemplatesProvider, err := ReopenTemplateProvider(p.namedTemplatesProvider)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if closeErr := CloseTemplateProvider(namedTemplatesProvider); err == nil {
+			err = closeErr
+		}
+	}()
+
+	type commonTemplate struct {
+		name    string
+		content string
+	}
+	commonTemplates := make([]commonTemplate, 0)
+	keyParts := []string{"common"}
 	for {
-		commonTemplate, err := p.namedTemplatesProvider.NextTemplate()
+		tpl, err := namedTemplatesProvider.NextTemplate()
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
-		slog.Info("Loading common template", slog.String("name", commonTemplate.Name))
+		p.log().Info("Loading common template", slog.String("name", tpl.Name))
 
-		content, err := io.ReadAll(commonTemplate.Reader)
-		commonTemplate.Reader.Close()
+		content, err := io.ReadAll(tpl.Reader)
+		tpl.Reader.Close()
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
-		_, err = baseTemplate.New(commonTemplate.Name).Parse(string(content))
-		if err != nil {
-			return nil, err
+		commonTemplates = append(commonTemplates, commonTemplate{name: tpl.Name, content: string(content)})
+		keyParts = append(keyParts, tpl.Name, string(content))
+	}
+
+	key := cacheKey(keyParts...)
+	baseTemplate, ok := p.cachedTemplate(key)
+	span.SetAttributes(tracing.Int("templates", len(commonTemplates)), tracing.Bool("cached", ok))
+	if !ok {
+		baseTemplate = template.New("").Funcs(p.functions)
+		for _, commonTemplate := range commonTemplates {
+			_, err := baseTemplate.New(commonTemplate.name).Parse(commonTemplate.content)
+			if err != nil {
+				return nil, "", err
+			}
+			p.observers.OnCommonTemplateLoaded(commonTemplate.name)
+		}
+
+		if p.templateCache != nil {
+			p.templateCache.Set(key, baseTemplate)
+		}
+	} else {
+		for _, commonTemplate := range commonTemplates {
+			p.observers.OnCommonTemplateLoaded(commonTemplate.name)
 		}
 	}
 
-	return baseTemplate, nil
+	return baseTemplate, key, nil
 }
 
-func (p *pipeline) Process(processData map[string]interface{}) error {
-	var err error
+func (p *pipeline) cachedTemplate(key string) (*template.Template, bool) {
+	if p.templateCache == nil {
+		return nil, false
+	}
+	return p.templateCache.Get(key)
+}
 
-	if p.dataPreprocessor != nil {
-		processData, err = p.dataPreprocessor(processData)
+// preprocessData applies the data preprocessors in the order they have been
+// added, each one receiving the output of the previous one.
+func (p *pipeline) preprocessData(ctx context.Context, data map[string]interface{}) (_ map[string]interface{}, err error) {
+	if len(p.dataPreprocessors) == 0 {
+		return data, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "pipeline.preprocessData")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	for i, preprocessor := range p.dataPreprocessors {
+		_, preprocessorSpan := tracing.Start(ctx, "pipeline.preprocessor", tracing.Int("index", i), tracing.String("name", preprocessor.name))
+		data, err = preprocessor.fn(data)
+		preprocessorSpan.RecordError(err)
+		preprocessorSpan.End()
 		if err != nil {
-			return err
+			return nil, &DataPreprocessorError{Index: i, Name: preprocessor.name, Err: err}
 		}
 	}
 
+	return data, nil
+}
+
+// run holds the state of a single Process call
+type run struct {
+	ctx              context.Context
+	templateProvider TemplateProvider
+	collector        Collector
+	parser           *templateParser
+	data             map[string]interface{}
+	result           *Result
+}
+
+func (p *pipeline) Process(processData map[string]interface{}) error {
+	_, err := p.ProcessWithResult(processData)
+	return err
+}
+
+func (p *pipeline) ProcessWithResult(processData map[string]interface{}) (*Result, error) {
+	return p.ProcessContext(context.Background(), processData)
+}
+
+func (p *pipeline) ProcessContext(ctx context.Context, processData map[string]interface{}) (*Result, error) {
+	if p.tracer != nil {
+		ctx = tracing.ContextWithTracer(ctx, p.tracer)
+	}
+	ctx, span := tracing.Start(ctx, "pipeline.Process")
+	p.observers.OnRunStarted()
+
+	start := time.Now()
+	result := &Result{}
+	err := p.process(ctx, processData, result)
+	result.Duration = time.Since(start)
+
+	p.observers.OnRunCompleted(result, err)
+	span.SetAttributes(tracing.Int("templates", len(result.Templates)), tracing.Int("files", len(result.Files)))
+	span.RecordError(err)
+	span.End()
+	return result, err
+}
+
+func (p *pipeline) process(ctx context.Context, processData map[string]interface{}, result *Result) (err error) {
+	processData, err = p.preprocessData(ctx, processData)
+	if err != nil {
+		return err
+	}
+	ctx = ContextWithData(ctx, processData)
+
 	// Load common templates once before processing main templates
-	baseTemplate, err := p.loadCommonTemplates()
+	baseTemplate, baseKey, err := p.loadCommonTemplates(ctx)
+	if err != nil {
+		return err
+	}
+
+	templateProvider, err := ReopenTemplateProvider(p.templateProvider)
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if closeErr := CloseTemplateProvider(templateProvider); err == nil {
+			err = closeErr
+		}
+	}()
+	SetTemplateProviderData(templateProvider, processData)
 
+	r := &run{
+		ctx:              ctx,
+		templateProvider: templateProvider,
+		collector:        NewCollectorRun(p.collector),
+		parser: &templateParser{
+			functions:        p.functions,
+			templateAwareFns: p.templateAwareFns,
+			baseTemplate:     baseTemplate,
+			baseKey:          baseKey,
+			cache:            p.templateCache,
+		},
+		data:   processData,
+		result: result,
+	}
 	for err == nil {
-		err = p.processNext(processData, baseTemplate)
+		err = p.processNext(r)
 	}
 	if errors.Is(err, io.EOF) {
-		return p.collector.OnPipelineCompleted()
+		_, span := tracing.Start(ctx, "pipeline.completeCollectors")
+		err = r.collector.OnPipelineCompleted()
+		span.RecordError(err)
+		span.End()
 	}
+	result.Files = CollectorResults(r.collector)
 	return err
 }
 
-func (p *pipeline) processNext(data map[string]interface{}, baseTemplate *template.Template) error {
-	result, err := _processNextTemplate(p.templateProvider, data, p.functions, p.templateAwareFns, baseTemplate)
+// processNext processes the next template, streaming it to the collector if it
+// supports it. Templates discarded by the collector are rendered anyway, so
+// that errors are reported regardless of the collectors configuration.
+func (p *pipeline) processNext(r *run) error {
+	start := time.Now()
+	result, render, err := _processNextTemplate(r.templateProvider, r.data, r.parser)
+	if result == nil { // the error is not related to a specific template
+		return err
+	}
+
+	p.log().Info("Processing template file", slog.String("path", result.Path))
+	p.observers.OnTemplateStarted(result.Path)
+
+	ctx, span := tracing.Start(r.ctx, "pipeline.template", tracing.String("path", result.Path))
+	result = result.WithContext(ctx)
+
+	templateResult := TemplateResult{Path: result.Path}
+	if err == nil {
+		err = p.collect(r, result, &countingRender{
+			render:   traceRender(ctx, render),
+			bytes:    &templateResult.Bytes,
+			rendered: func(bytes int64) { p.observers.OnTemplateRendered(result.Path, bytes) },
+		})
+	}
+	templateResult.Duration = time.Since(start)
+	templateResult.Err = err
+	r.result.Templates = append(r.result.Templates, templateResult)
+
+	span.SetAttributes(tracing.Int64("bytes", templateResult.Bytes))
+	span.RecordError(err)
+	span.End()
+
 	if err != nil {
+		p.observers.OnTemplateFailed(result.Path, err)
+	} else {
+		p.observers.OnTemplateCollected(templateResult)
+	}
+
+	return err
+}
+
+func (p *pipeline) collect(r *run, result *Template, render *countingRender) error {
+	err := CollectStream(r.collector, result, render.Render)
+	if err != nil || render.invoked {
+		return err
+	}
+
+	return render.Render(io.Discard)
+}
+
+// countingRender wraps a RenderFunc, to track whether it has been invoked and
+// how many bytes it wrote. The rendered callback, if any, is invoked when the
+// rendering succeeds.
+type countingRender struct {
+	render   RenderFunc
+	invoked  bool
+	bytes    *int64
+	rendered func(bytes int64)
+}
+
+func (r *countingRender) Render(w io.Writer) error {
+	r.invoked = true
+	err := r.render(&countingWriter{writer: w, bytes: r.bytes})
+	if err == nil && r.rendered != nil {
+		r.rendered(*r.bytes)
+	}
+	return err
+}
+
+// traceRender wraps a RenderFunc, to trace the rendering in a span
+func traceRender(ctx context.Context, render RenderFunc) RenderFunc {
+	return func(w io.Writer) error {
+		_, span := tracing.Start(ctx, "pipeline.render")
+		err := render(w)
+		span.RecordError(err)
+		span.End()
 		return err
 	}
+}
+
+type countingWriter struct {
+	writer io.Writer
+	bytes  *int64
+}
 
-	return p.collector.Collect(result)
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	*w.bytes += int64(n)
+	return n, err
 }
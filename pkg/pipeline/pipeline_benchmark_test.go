@@ -0,0 +1,66 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+const benchmarkTemplate = `package {{ .Values.package }}
+
+{{ range $i, $field := .Values.fields }}
+// {{ $field | title }} is field number {{ $i }} of {{ template "typeName" $ }}
+func (t *{{ template "typeName" $ }}) {{ $field | title }}() string {
+	{{- if eq $i 0 }}
+	return "first"
+	{{- else }}
+	return "{{ $field }}"
+	{{- end }}
+}
+{{ end }}
+`
+
+func BenchmarkPipeline_Process(b *testing.B) {
+	data := map[string]interface{}{
+		"Values": map[string]interface{}{
+			"package": "benchmark",
+			"type":    "Entity",
+			"fields":  []string{"id", "name", "description", "createdAt", "updatedAt"},
+		},
+	}
+	mainTemplates := make(map[string]string, 50)
+	for i := 0; i < 50; i++ {
+		mainTemplates[fmt.Sprintf("file-%d.go", i)] = benchmarkTemplate
+	}
+	commonTemplates := map[string]string{
+		"typeName": `{{ define "typeName" }}{{ .Values.type }}{{ end }}`,
+	}
+
+	for _, withCache := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cache=%v", withCache), func(b *testing.B) {
+			var cache TemplateCache
+			if withCache {
+				cache = NewTemplateCache()
+			}
+
+			p, err := NewPipelineBuilder().
+				WithFunctions(template.FuncMap{"title": strings.ToTitle}).
+				WithTemplateProvider(newMapTemplateProvider(mainTemplates)).
+				WithNamedTemplatesProvider(newMapTemplateProvider(commonTemplates)).
+				WithCollector(&discardCollector{}).
+				WithTemplateCache(cache).
+				Build()
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			for i := 0; i < b.N; i++ {
+				err = p.Process(data)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
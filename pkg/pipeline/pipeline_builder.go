@@ -2,9 +2,11 @@ package pipeline
 
 import (
 	"errors"
+	"log/slog"
 	"text/template"
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/templates"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 )
 
 type PipelineBuilder interface {
@@ -12,9 +14,14 @@ type PipelineBuilder interface {
 	WithCollector(p Collector) *pipelineBuilder
 	WithDataPreprocessor(fn DataPreprocessor) *pipelineBuilder
 	WithFunctions(functions template.FuncMap) *pipelineBuilder
+	WithLogger(logger *slog.Logger) *pipelineBuilder
+	WithNamedDataPreprocessor(name string, fn DataPreprocessor) *pipelineBuilder
 	WithNamedTemplatesProvider(p TemplateProvider) *pipelineBuilder
+	WithObserver(o Observer) *pipelineBuilder
 	WithTemplateAwareFunctions(functions templates.TemplateAwareFuncMap) *pipelineBuilder
+	WithTemplateCache(cache TemplateCache) *pipelineBuilder
 	WithTemplateProvider(p TemplateProvider) *pipelineBuilder
+	WithTracer(tracer tracing.Tracer) *pipelineBuilder
 }
 
 type pipelineBuilder struct {
@@ -46,8 +53,21 @@ func (b *pipelineBuilder) WithCollector(p Collector) *pipelineBuilder {
 	return b
 }
 
+// WithDataPreprocessor adds a preprocessor to the chain applied to the data
+// before processing the templates. Preprocessors are applied in the order they
+// are added, each one receiving the output of the previous one; nil
+// preprocessors are ignored.
 func (b *pipelineBuilder) WithDataPreprocessor(fn DataPreprocessor) *pipelineBuilder {
-	b.p.dataPreprocessor = fn
+	return b.WithNamedDataPreprocessor("", fn)
+}
+
+// WithNamedDataPreprocessor adds a preprocessor to the chain like
+// WithDataPreprocessor, the name identifies it in the returned errors (see
+// DataPreprocessorError).
+func (b *pipelineBuilder) WithNamedDataPreprocessor(name string, fn DataPreprocessor) *pipelineBuilder {
+	if fn != nil {
+		b.p.dataPreprocessors = append(b.p.dataPreprocessors, namedDataPreprocessor{name: name, fn: fn})
+	}
 	return b
 }
 
@@ -56,17 +76,47 @@ func (b *pipelineBuilder) WithFunctions(functions template.FuncMap) *pipelineBui
 	return b
 }
 
+// WithLogger sets the logger used by the pipeline, slog.Default() is used if
+// not set.
+func (b *pipelineBuilder) WithLogger(logger *slog.Logger) *pipelineBuilder {
+	b.p.logger = logger
+	return b
+}
+
 func (b *pipelineBuilder) WithNamedTemplatesProvider(p TemplateProvider) *pipelineBuilder {
 	b.p.namedTemplatesProvider = p
 	return b
 }
 
+// WithObserver adds an observer notified of the events of the pipeline runs.
+// Observers are notified in the order they are added.
+func (b *pipelineBuilder) WithObserver(o Observer) *pipelineBuilder {
+	b.p.observers = append(b.p.observers, o)
+	return b
+}
+
 func (b *pipelineBuilder) WithTemplateAwareFunctions(functions templates.TemplateAwareFuncMap) *pipelineBuilder {
 	b.p.templateAwareFns = functions
 	return b
 }
 
+// WithTemplateCache sets the cache used to store the compiled templates, so
+// that repeated Process calls with the same templates only execute them; the
+// templates are still read at every run, to compute their keys. The cache can
+// be shared with other pipelines configured with the same functions.
+func (b *pipelineBuilder) WithTemplateCache(cache TemplateCache) *pipelineBuilder {
+	b.p.templateCache = cache
+	return b
+}
+
 func (b *pipelineBuilder) WithTemplateProvider(p TemplateProvider) *pipelineBuilder {
 	b.p.templateProvider = p
 	return b
 }
+
+// WithTracer sets the tracer used to trace the stages of the runs, it
+// overrides the tracer in the context passed to ProcessContext, if any.
+func (b *pipelineBuilder) WithTracer(tracer tracing.Tracer) *pipelineBuilder {
+	b.p.tracer = tracer
+	return b
+}
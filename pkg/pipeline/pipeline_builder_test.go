@@ -2,6 +2,8 @@ package pipeline
 
 import (
 	"errors"
+	"io"
+	"log/slog"
 	"testing"
 	"text/template"
 
@@ -84,7 +86,7 @@ func Test_builder_Build(t *testing.T) {
 			builder := NewPipelineBuilder()
 
 			builder = builder.
-				WithDataPreprocessor(tt.pipeline.dataPreprocessor).
+				WithDataPreprocessor(nil).
 				WithFunctions(tt.pipeline.functions).
 				WithTemplateProvider(tt.pipeline.templateProvider).
 				WithCollector(tt.pipeline.collector).
@@ -103,3 +105,18 @@ func Test_builder_Build(t *testing.T) {
 		})
 	}
 }
+
+func Test_builder_WithLogger(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	got, err := NewPipelineBuilder().
+		WithFunctions(template.FuncMap{"test": Test_builder_WithLogger}).
+		WithTemplateProvider(&templateProviderMock{}).
+		WithCollector(&collectorMock{}).
+		WithLogger(logger).
+		Build()
+
+	assert.NoError(t, err)
+	assert.Same(t, logger, got.(*pipeline).log())
+	assert.Same(t, slog.Default(), (&pipeline{}).log())
+}
@@ -1,16 +1,21 @@
 package pipeline
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"io"
+	"log/slog"
 	"strings"
 	"testing"
 	"text/template"
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/templates"
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 	"github.com/pasdam/go-template-map-loader/pkg/tm"
 	"github.com/pasdam/go-utils/pkg/assertutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_pipeline_loadNamedTemplates(t *testing.T) {
@@ -80,6 +85,7 @@ func Test_pipeline_loadNamedTemplates(t *testing.T) {
 					Name:   "invalid",
 					Reader: io.NopCloser(strings.NewReader("{{ invalid template syntax")),
 				}, nil).Once()
+				m.On("NextTemplate").Return(nil, io.EOF)
 			},
 			wantErr: errors.New("template: invalid:1: function \"invalid\" not defined"),
 		},
@@ -100,7 +106,7 @@ func Test_pipeline_loadNamedTemplates(t *testing.T) {
 				p.namedTemplatesProvider = commonProvider
 			}
 
-			got, err := p.loadCommonTemplates()
+			got, gotKey, err := p.loadCommonTemplates(context.Background())
 
 			if tt.wantErr != nil {
 				assert.NotNil(t, err)
@@ -110,8 +116,10 @@ func Test_pipeline_loadNamedTemplates(t *testing.T) {
 				assert.Nil(t, err)
 				if !tt.withProvider {
 					assert.Nil(t, got)
+					assert.Empty(t, gotKey)
 				} else {
 					assert.NotNil(t, got)
+					assert.NotEmpty(t, gotKey)
 					for _, name := range tt.wantTemplateNames {
 						assert.NotNil(t, got.Lookup(name), "Template %s should exist", name)
 					}
@@ -273,7 +281,7 @@ func Test_pipeline_Process(t *testing.T) {
 			mocks: mocks{
 				dataPreprocessorError: errors.New("some-data-processor-error"),
 			},
-			wantErr: errors.New("some-data-processor-error"),
+			wantErr: &DataPreprocessorError{Index: 0, Err: errors.New("some-data-processor-error")},
 		},
 	}
 	for _, tt := range tests {
@@ -295,19 +303,19 @@ func Test_pipeline_Process(t *testing.T) {
 			expectedData := data
 			if tt.fields.withDataPreprocessor {
 				expectedData = tm.WithPrefix("some-preprocessor-prefix", data)
-				p.dataPreprocessor = func(m map[string]interface{}) (map[string]interface{}, error) {
+				p.dataPreprocessors = []namedDataPreprocessor{{fn: func(m map[string]interface{}) (map[string]interface{}, error) {
 					assert.Equal(t, data, m)
 					if tt.mocks.dataPreprocessorError != nil {
 						return nil, tt.mocks.dataPreprocessorError
 					}
 					return expectedData, nil
-				}
+				}}}
 			}
 			mockProcessNextTemplate(t, templateProvider, expectedData, functions, templateAwareFnGen, tt.mocks.nextTemplateRes)
 			assert.Len(t, tt.mocks.nextTemplateRes, len(tt.mocks.collectingErrs))
 			for i := 0; i < len(tt.mocks.nextTemplateRes); i++ {
 				if tt.mocks.nextTemplateRes[i].err == nil {
-					collector.On("Collect", tt.mocks.nextTemplateRes[i].data).Return(tt.mocks.collectingErrs[i])
+					collector.On("Collect", mock.Anything).Return(tt.mocks.collectingErrs[i]).Once()
 				}
 			}
 			collector.On("OnPipelineCompleted").Return(tt.mocks.completeError)
@@ -327,19 +335,281 @@ type nextTemplateResult struct {
 func mockProcessNextTemplate(t *testing.T, expectedProcessor TemplateProvider, expectedData interface{}, expectedFuncMap template.FuncMap, expectedTemplateAwareFnGen templates.TemplateAwareFuncMap, nextTemplateRes []*nextTemplateResult) {
 	originalValue := _processNextTemplate
 	count := 0
-	_processNextTemplate = func(gotProcessor TemplateProvider, gotData interface{}, gotFuncMap template.FuncMap, gotTemplateAwareFnGen templates.TemplateAwareFuncMap, gotBaseTemplate *template.Template) (*Template, error) {
+	_processNextTemplate = func(gotProcessor TemplateProvider, gotData interface{}, gotParser *templateParser) (*Template, RenderFunc, error) {
 		assert.Equal(t, expectedProcessor, gotProcessor)
 		assert.Equal(t, expectedData, gotData)
-		assert.Equal(t, expectedFuncMap, gotFuncMap)
-		assert.Equal(t, expectedTemplateAwareFnGen, gotTemplateAwareFnGen)
+		assert.Equal(t, expectedFuncMap, gotParser.functions)
+		assert.Equal(t, expectedTemplateAwareFnGen, gotParser.templateAwareFns)
 
 		if len(nextTemplateRes) == 0 {
-			return nil, io.EOF
+			return nil, nil, io.EOF
 		}
 		assert.Less(t, count, len(nextTemplateRes))
 		res := nextTemplateRes[count]
 		count++
-		return res.data, res.err
+		if res.err != nil {
+			return nil, nil, res.err
+		}
+		return &Template{Path: res.data.Path}, RenderReader(res.data.Reader), nil
 	}
 	t.Cleanup(func() { _processNextTemplate = originalValue })
 }
+
+func Test_pipeline_loadCommonTemplates_ShouldReuseCachedTemplates(t *testing.T) {
+	newProvider := func(content string) *templateProviderMock {
+		m := &templateProviderMock{}
+		m.On("NextTemplate").Return(&Template{
+			Name:   "header",
+			Reader: io.NopCloser(strings.NewReader(content)),
+		}, nil).Once()
+		m.On("NextTemplate").Return(nil, io.EOF)
+		return m
+	}
+	p := &pipeline{
+		functions:     template.FuncMap{"dummy": func() string { return "" }},
+		templateCache: NewTemplateCache(),
+	}
+
+	p.namedTemplatesProvider = newProvider("HEADER")
+	first, firstKey, err := p.loadCommonTemplates(context.Background())
+	assert.NoError(t, err)
+
+	p.namedTemplatesProvider = newProvider("HEADER")
+	second, secondKey, err := p.loadCommonTemplates(context.Background())
+	assert.NoError(t, err)
+
+	p.namedTemplatesProvider = newProvider("OTHER HEADER")
+	third, thirdKey, err := p.loadCommonTemplates(context.Background())
+	assert.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, firstKey, secondKey)
+	assert.NotSame(t, first, third)
+	assert.NotEqual(t, firstKey, thirdKey)
+}
+
+func Test_pipeline_processNext_ShouldRenderTemplatesDiscardedByCollectors(t *testing.T) {
+	templateProvider := &templateProviderMock{}
+	templateProvider.On("NextTemplate").Return(&Template{
+		Path:   "some-path",
+		Reader: io.NopCloser(strings.NewReader("{{ template \"missing\" }}")),
+	}, nil)
+	discardingCollector := &discardingStreamingCollector{}
+	p := &pipeline{}
+
+	err := p.processNext(&run{
+		ctx:              context.Background(),
+		templateProvider: templateProvider,
+		collector:        discardingCollector,
+		parser:           &templateParser{},
+		result:           &Result{},
+	})
+
+	assert.ErrorContains(t, err, "template \"missing\" not defined")
+	assert.Equal(t, 1, discardingCollector.calls)
+}
+
+type discardingStreamingCollector struct {
+	collectorMock
+
+	calls int
+}
+
+func (c *discardingStreamingCollector) CollectStream(args *Template, render RenderFunc) error {
+	c.calls++
+	return nil
+}
+
+func Test_pipeline_Process_ShouldRejectTemplatesNamedLikeCommonTemplates(t *testing.T) {
+	p, err := NewPipelineBuilder().
+		WithFunctions(map[string]any{"dummy": func() string { return "" }}).
+		WithTemplateProvider(newMapTemplateProvider(map[string]string{"header": "some-content"})).
+		WithNamedTemplatesProvider(newMapTemplateProvider(map[string]string{"header": "abc"})).
+		WithCollector(&discardCollector{}).
+		WithTemplateCache(NewTemplateCache()).
+		Build()
+	assert.NoError(t, err)
+
+	err = p.Process(nil)
+
+	assertutils.AssertEqualErrors(t, errors.New("template header: the name is already used by a common template"), err)
+}
+
+func Test_pipeline_Process_logger(t *testing.T) {
+	logs := &bytes.Buffer{}
+	p, err := NewPipelineBuilder().
+		WithFunctions(map[string]any{"dummy": func() string { return "" }}).
+		WithTemplateProvider(newMapTemplateProvider(map[string]string{"file1": "some-content"})).
+		WithNamedTemplatesProvider(newMapTemplateProvider(map[string]string{"common": "abc"})).
+		WithCollector(&discardCollector{}).
+		WithLogger(slog.New(slog.NewTextHandler(logs, nil))).
+		Build()
+	assert.NoError(t, err)
+
+	assert.NoError(t, p.Process(nil))
+
+	assert.Contains(t, logs.String(), `level=INFO msg="Loading common template" name=common`)
+	assert.Contains(t, logs.String(), `level=INFO msg="Processing template file" path=file1`)
+}
+
+func Test_pipeline_ProcessContext_tracing(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	var collectedCtx context.Context
+	p, err := NewPipelineBuilder().
+		WithFunctions(map[string]any{"dummy": func() string { return "" }}).
+		WithTemplateProvider(newMapTemplateProvider(map[string]string{"file1": "some-content", "file2": "{{ .Missing"})).
+		WithNamedTemplatesProvider(newMapTemplateProvider(map[string]string{"common": "abc"})).
+		WithDataPreprocessor(func(data map[string]interface{}) (map[string]interface{}, error) { return data, nil }).
+		WithCollector(&contextCollector{ctx: &collectedCtx}).
+		Build()
+	assert.NoError(t, err)
+	ctx, parent := recorder.Start(tracing.ContextWithTracer(context.Background(), recorder), "some-request")
+
+	_, err = p.ProcessContext(ctx, nil)
+	parent.End()
+
+	assert.Error(t, err)
+	spans := recorder.Spans()
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name)
+		assert.True(t, span.Ended, span.Name)
+	}
+	assert.Equal(t, []string{
+		"some-request",
+		"pipeline.Process",
+		"pipeline.preprocessData",
+		"pipeline.preprocessor",
+		"pipeline.loadCommonTemplates",
+		"pipeline.template",
+		"pipeline.render",
+		"pipeline.template",
+	}, names)
+	assert.Equal(t, spans[0].ID, spans[1].ParentID)
+	assert.Equal(t, spans[1].ID, spans[2].ParentID)
+	assert.Equal(t, spans[2].ID, spans[3].ParentID)
+	assert.Equal(t, spans[1].ID, spans[4].ParentID)
+	assert.Equal(t, spans[1].ID, spans[5].ParentID)
+	assert.Equal(t, spans[5].ID, spans[6].ParentID)
+	assert.Equal(t, spans[1].ID, spans[7].ParentID)
+
+	path, _ := spans[5].Attribute("path")
+	assert.Equal(t, "file1", path)
+	bytes, _ := spans[5].Attribute("bytes")
+	assert.Equal(t, int64(12), bytes)
+	cached, _ := spans[4].Attribute("cached")
+	assert.Equal(t, false, cached)
+	assert.EqualError(t, spans[7].Err, "template: file2:1: unclosed action")
+	assert.Equal(t, spans[7].Err, spans[1].Err)
+
+	// the collector receives the context of the template span
+	_, span := tracing.Start(collectedCtx, "some-collector")
+	span.End()
+	spans = recorder.Spans()
+	assert.Equal(t, spans[5].ID, spans[len(spans)-1].ParentID)
+}
+
+func Test_pipeline_ProcessContext_tracerOfThePipeline(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	p, err := NewPipelineBuilder().
+		WithFunctions(map[string]any{"dummy": func() string { return "" }}).
+		WithTemplateProvider(newMapTemplateProvider(map[string]string{"file1": "some-content"})).
+		WithCollector(&discardCollector{}).
+		WithTracer(recorder).
+		Build()
+	assert.NoError(t, err)
+
+	assert.NoError(t, p.Process(nil))
+
+	assert.Len(t, recorder.SpansNamed("pipeline.Process"), 1)
+	assert.Len(t, recorder.SpansNamed("pipeline.template"), 1)
+	assert.Len(t, recorder.SpansNamed("pipeline.completeCollectors"), 1)
+}
+
+// contextCollector stores the context of the last collected template
+type contextCollector struct {
+	discardCollector
+	ctx *context.Context
+}
+
+func (c *contextCollector) Collect(args *Template) error {
+	*c.ctx = args.Context()
+	return c.discardCollector.Collect(args)
+}
+
+// pathsProvider returns templates with the specified paths, in order
+type pathsProvider struct {
+	paths []string
+}
+
+func (p *pathsProvider) NextTemplate() (*Template, error) {
+	if len(p.paths) == 0 {
+		return nil, io.EOF
+	}
+	path := p.paths[0]
+	p.paths = p.paths[1:]
+	return &Template{Path: path, Reader: io.NopCloser(strings.NewReader(path))}, nil
+}
+
+// pathsCollector records the paths of the collected templates
+type pathsCollector struct {
+	discardCollector
+	paths []string
+}
+
+func (c *pathsCollector) Collect(args *Template) error {
+	c.paths = append(c.paths, args.Path)
+	return c.discardCollector.Collect(args)
+}
+
+func Test_pipeline_ProcessWithResult_order(t *testing.T) {
+	paths := []string{"b.txt", "a/x.txt", "a.txt", "c.txt"}
+	collector := &pathsCollector{}
+	p, err := NewPipelineBuilder().
+		WithFunctions(template.FuncMap{"dummy": func() string { return "" }}).
+		WithTemplateProvider(&pathsProvider{paths: append([]string{}, paths...)}).
+		WithCollector(collector).
+		Build()
+	assert.NoError(t, err)
+
+	result, err := p.ProcessWithResult(nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, paths, collector.paths)
+	processed := make([]string, 0, len(result.Templates))
+	for _, tpl := range result.Templates {
+		processed = append(processed, tpl.Path)
+	}
+	assert.Equal(t, paths, processed)
+}
+
+// dataProvider records the data set by the pipeline
+type dataProvider struct {
+	pathsProvider
+	data map[string]interface{}
+}
+
+func (p *dataProvider) SetData(data map[string]interface{}) {
+	p.data = data
+}
+
+func Test_pipeline_Process_data(t *testing.T) {
+	provider := &dataProvider{pathsProvider: pathsProvider{paths: []string{"a.txt"}}}
+	var ctx context.Context
+	p, err := NewPipelineBuilder().
+		WithFunctions(template.FuncMap{"dummy": func() string { return "" }}).
+		WithDataPreprocessor(func(data map[string]interface{}) (map[string]interface{}, error) {
+			return map[string]interface{}{"preprocessed": data["key"]}, nil
+		}).
+		WithTemplateProvider(provider).
+		WithCollector(&contextCollector{ctx: &ctx}).
+		Build()
+	assert.NoError(t, err)
+
+	err = p.Process(map[string]interface{}{"key": "value"})
+
+	assert.NoError(t, err)
+	expected := map[string]interface{}{"preprocessed": "value"}
+	assert.Equal(t, expected, provider.data)
+	assert.Equal(t, expected, DataFromContext(ctx))
+}
@@ -2,32 +2,43 @@ package pipeline
 
 import (
 	"io"
-	"log/slog"
-	"text/template"
-
-	"github.com/go-scaffold/go-sdk/v2/pkg/templates"
 )
 
-var _processTemplate = templates.ProcessTemplateWithBaseTemplate
+var readAll = io.ReadAll
 
-func processNextTemplate(templateProvider TemplateProvider, data interface{}, funcMap template.FuncMap, templateAwareFnGen templates.TemplateAwareFuncMap, baseTemplate *template.Template) (*Template, error) {
+// processNextTemplate parses the next template of the provider, and returns
+// its description with the function that renders it with the specified data.
+// The description is returned also if the template cannot be parsed, so that
+// the error can be associated to it.
+func processNextTemplate(templateProvider TemplateProvider, data interface{}, parser *templateParser) (*Template, RenderFunc, error) {
 	template, err := templateProvider.NextTemplate()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	slog.Info("Processing template file", slog.String("path", template.Path))
-
 	templateReader := template.Reader
 	defer templateReader.Close()
 
-	resultReader, err := _processTemplate(templateReader, data, funcMap, templateAwareFnGen, baseTemplate)
+	result := &Template{
+		Path:   template.Path,
+		Source: template.Path,
+		Origin: template.Origin,
+		Mode:   template.Mode,
+	}
+
+	content, err := readAll(templateReader)
 	if err != nil {
-		return nil, err
+		return result, nil, err
 	}
 
-	return &Template{
-		Path:   template.Path,
-		Reader: io.NopCloser(resultReader),
-	}, nil
+	tpl, err := parser.parse(template.Path, string(content))
+	if err != nil {
+		return result, nil, err
+	}
+
+	render := func(w io.Writer) error {
+		return tpl.Execute(w, data)
+	}
+
+	return result, render, nil
 }
@@ -8,16 +8,17 @@ import (
 	"text/template"
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/templates"
-	"github.com/pasdam/go-io-utilx/pkg/ioutilx"
 	"github.com/pasdam/go-utils/pkg/assertutils"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_processNextTemplate(t *testing.T) {
 	type mocks struct {
-		templateContent   string
-		nextTemplateErr   error
-		renderTemplateErr error
+		templateContent string
+		nextTemplateErr error
+		readErr         error
+		parseErr        error
+		parsedContent   string
 	}
 	tests := []struct {
 		name        string
@@ -27,8 +28,11 @@ func Test_processNextTemplate(t *testing.T) {
 		wantErr     error
 	}{
 		{
-			name:        "Should return processed data if no error occurs",
-			mocks:       mocks{},
+			name: "Should return processed data if no error occurs",
+			mocks: mocks{
+				templateContent: "some-template-content",
+				parsedContent:   "some-reader-content",
+			},
 			wantPath:    "some-path",
 			wantContent: "some-reader-content",
 		},
@@ -40,90 +44,98 @@ func Test_processNextTemplate(t *testing.T) {
 			wantErr: errors.New("some next template error"),
 		},
 		{
-			name: "Should propagate the error if render template returns on",
+			name: "Should propagate the error if reading the template returns one",
+			mocks: mocks{
+				readErr: errors.New("some read error"),
+			},
+			wantPath: "some-path",
+			wantErr:  errors.New("some read error"),
+		},
+		{
+			name: "Should propagate the error if parsing the template returns one",
 			mocks: mocks{
-				renderTemplateErr: errors.New("some render template error"),
+				parseErr: errors.New("some parse error"),
 			},
-			wantErr: errors.New("some render template error"),
+			wantPath: "some-path",
+			wantErr:  errors.New("some parse error"),
+		},
+		{
+			name: "Should propagate the error if executing the template returns one",
+			mocks: mocks{
+				parsedContent: "{{ template \"missing\" }}",
+			},
+			wantPath: "some-path",
+			wantErr:  errors.New("template: some-path:1:12: executing \"some-path\" at <{{template \"missing\"}}>: template \"missing\" not defined"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			templateProvider := &templateProviderMock{}
 			data := make(map[string]string)
-			funcMap := make(template.FuncMap)
-			templateAwareFnGen := make(templates.TemplateAwareFuncMap)
+			parser := &templateParser{
+				functions:        make(template.FuncMap),
+				templateAwareFns: make(templates.TemplateAwareFuncMap),
+			}
 
-			var nextTemplate *Template
 			if tt.mocks.nextTemplateErr == nil {
-				templateReader := io.NopCloser(strings.NewReader(tt.mocks.templateContent))
-				nextTemplate = &Template{
-					Reader: templateReader,
+				templateProvider.On("NextTemplate").Return(&Template{
+					Reader: io.NopCloser(strings.NewReader(tt.mocks.templateContent)),
 					Path:   tt.wantPath,
+					Origin: "some-origin",
+				}, nil)
+				if tt.mocks.readErr != nil {
+					mockReadAll(t, tt.mocks.readErr)
 				}
-				templateProvider.On("NextTemplate").Return(nextTemplate, nil)
-				mockProcessTemplate(t, templateReader, data, funcMap, templateAwareFnGen, nil, tt.wantContent, tt.mocks.renderTemplateErr)
+				mockParseTemplate(t, tt.wantPath, tt.mocks.templateContent, parser, tt.mocks.parsedContent, tt.mocks.parseErr)
 
 			} else {
 				templateProvider.On("NextTemplate").Return(nil, tt.mocks.nextTemplateErr)
 			}
 
-			got, err := processNextTemplate(templateProvider, data, funcMap, templateAwareFnGen, nil)
+			got, render, err := processNextTemplate(templateProvider, data, parser)
 
-			if tt.wantErr == nil {
+			if err == nil {
 				assert.NotNil(t, got)
+				assert.Nil(t, got.Reader)
 				assert.Equal(t, tt.wantPath, got.Path)
-				assert.Equal(t, tt.wantContent, ioutilx.ReaderToString(got.Reader))
+				assert.Equal(t, tt.wantPath, got.Source)
+				assert.Equal(t, "some-origin", got.Origin)
+				var content strings.Builder
+				err = render(&content)
+				assert.Equal(t, tt.wantContent, content.String())
 
 			} else {
-				assert.Nil(t, got)
+				if tt.mocks.nextTemplateErr != nil {
+					assert.Nil(t, got)
+				} else {
+					assert.Equal(t, tt.wantPath, got.Path)
+				}
+				assert.Nil(t, render)
 			}
 			assertutils.AssertEqualErrors(t, tt.wantErr, err)
 		})
 	}
 }
 
-func mockProcessTemplate(t *testing.T, expectedReader io.Reader, expectedData interface{}, expectedFuncMap template.FuncMap, expectedTemplateAwareFnGen templates.TemplateAwareFuncMap, expectedBaseTemplate *template.Template, content string, err error) {
-	originalValue := _processTemplate
-	_processTemplate = func(gotReader io.Reader, gotData interface{}, gotFuncMap template.FuncMap, gotTemplateAwareFnGen templates.TemplateAwareFuncMap, gotBaseTemplate *template.Template) (io.Reader, error) {
-		assert.Equal(t, expectedReader, gotReader)
-		assert.Equal(t, expectedData, gotData)
-		assert.Equal(t, expectedFuncMap, gotFuncMap)
-		assert.Equal(t, expectedTemplateAwareFnGen, gotTemplateAwareFnGen)
-		assert.Equal(t, expectedBaseTemplate, gotBaseTemplate)
-		if err == nil {
-			return strings.NewReader(content), nil
-		}
-		return nil, err
-	}
-	t.Cleanup(func() { _processTemplate = originalValue })
-}
-
 func Test_processNextTemplate_WithBaseTemplate(t *testing.T) {
-	type mocks struct {
-		templateContent   string
-		nextTemplateErr   error
-		renderTemplateErr error
-	}
 	tests := []struct {
 		name         string
-		mocks        mocks
 		baseTemplate *template.Template
+		content      string
 		wantContent  string
 		wantPath     string
-		wantErr      error
 	}{
 		{
-			name:         "Should pass base template to process template",
-			mocks:        mocks{},
-			baseTemplate: template.Must(template.New("base").Parse("base content")),
+			name:         "Should use base template to process template",
+			baseTemplate: template.Must(template.New("").New("base").Parse("base content")),
+			content:      "{{ template \"base\" }} processed-with-base",
 			wantPath:     "some-path",
-			wantContent:  "processed-with-base",
+			wantContent:  "base content processed-with-base",
 		},
 		{
 			name:         "Should work with nil base template",
-			mocks:        mocks{},
 			baseTemplate: nil,
+			content:      "processed-without-base",
 			wantPath:     "another-path",
 			wantContent:  "processed-without-base",
 		},
@@ -131,35 +143,46 @@ func Test_processNextTemplate_WithBaseTemplate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			templateProvider := &templateProviderMock{}
-			data := make(map[string]string)
-			funcMap := make(template.FuncMap)
-			templateAwareFnGen := make(templates.TemplateAwareFuncMap)
-
-			var nextTemplate *Template
-			if tt.mocks.nextTemplateErr == nil {
-				templateReader := io.NopCloser(strings.NewReader(tt.mocks.templateContent))
-				nextTemplate = &Template{
-					Reader: templateReader,
-					Path:   tt.wantPath,
-				}
-				templateProvider.On("NextTemplate").Return(nextTemplate, nil)
-				mockProcessTemplate(t, templateReader, data, funcMap, templateAwareFnGen, tt.baseTemplate, tt.wantContent, tt.mocks.renderTemplateErr)
-
-			} else {
-				templateProvider.On("NextTemplate").Return(nil, tt.mocks.nextTemplateErr)
+			templateProvider.On("NextTemplate").Return(&Template{
+				Reader: io.NopCloser(strings.NewReader(tt.content)),
+				Path:   tt.wantPath,
+			}, nil)
+			parser := &templateParser{
+				functions:    make(template.FuncMap),
+				baseTemplate: tt.baseTemplate,
 			}
 
-			got, err := processNextTemplate(templateProvider, data, funcMap, templateAwareFnGen, tt.baseTemplate)
+			got, render, err := processNextTemplate(templateProvider, nil, parser)
 
-			if tt.wantErr == nil {
-				assert.NotNil(t, got)
-				assert.Equal(t, tt.wantPath, got.Path)
-				assert.Equal(t, tt.wantContent, ioutilx.ReaderToString(got.Reader))
-
-			} else {
-				assert.Nil(t, got)
-			}
-			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPath, got.Path)
+			var content strings.Builder
+			assert.NoError(t, render(&content))
+			assert.Equal(t, tt.wantContent, content.String())
 		})
 	}
 }
+
+func mockReadAll(t *testing.T, err error) {
+	originalValue := readAll
+	readAll = func(r io.Reader) ([]byte, error) {
+		return nil, err
+	}
+	t.Cleanup(func() { readAll = originalValue })
+}
+
+func mockParseTemplate(t *testing.T, expectedName string, expectedContent string, expectedParser *templateParser, content string, err error) {
+	originalValue := _parseTemplate
+	_parseTemplate = func(gotName string, gotContent string, gotFuncMap template.FuncMap, gotTemplateAwareFnGen templates.TemplateAwareFuncMap, gotBaseTemplate *template.Template) (*template.Template, error) {
+		assert.Equal(t, expectedName, gotName)
+		assert.Equal(t, expectedContent, gotContent)
+		assert.Equal(t, expectedParser.functions, gotFuncMap)
+		assert.Equal(t, expectedParser.templateAwareFns, gotTemplateAwareFnGen)
+		assert.Equal(t, expectedParser.baseTemplate, gotBaseTemplate)
+		if err != nil {
+			return nil, err
+		}
+		return template.New(gotName).Parse(content)
+	}
+	t.Cleanup(func() { _parseTemplate = originalValue })
+}
@@ -0,0 +1,103 @@
+package pipeline
+
+import "time"
+
+// FileStatus describes what a collector did with a file
+type FileStatus string
+
+const (
+	// FileWritten is the status of files written by a collector
+	FileWritten FileStatus = "written"
+
+	// FileUnchanged is the status of files not written because their content
+	// didn't change
+	FileUnchanged FileStatus = "unchanged"
+
+	// FileRemoved is the status of files removed by a collector (i.e. because
+	// they were not generated by the run)
+	FileRemoved FileStatus = "removed"
+
+	// FileSkipped is the status of files discarded by a collector (i.e. a
+	// filter)
+	FileSkipped FileStatus = "skipped"
+)
+
+// FileResult is the outcome of a file handled by a collector
+type FileResult struct {
+	// Path is the path of the file, relative to the output of the collector
+	Path string
+
+	// Status describes what the collector did with the file
+	Status FileStatus
+
+	// Bytes is the size of the file, if it was written
+	Bytes int64
+}
+
+// TemplateResult is the outcome of the processing of a main template
+type TemplateResult struct {
+	// Path is the path of the template
+	Path string
+
+	// Duration is the time spent rendering and collecting the template
+	Duration time.Duration
+
+	// Bytes is the size of the rendered content
+	Bytes int64
+
+	// Err is the error that occurred while processing the template, if any
+	Err error
+}
+
+// Result is the summary of a pipeline run
+type Result struct {
+	// Templates contains the outcome of each processed template, in the order
+	// they were processed
+	Templates []TemplateResult
+
+	// Files contains the outcome of the files reported by the collectors
+	Files []FileResult
+
+	// Duration is the total time of the run
+	Duration time.Duration
+}
+
+// Count returns the number of files with the specified status
+func (r *Result) Count(status FileStatus) int {
+	count := 0
+	for _, file := range r.Files {
+		if file.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// BytesWritten returns the total size of the files written by the collectors
+func (r *Result) BytesWritten() int64 {
+	var total int64
+	for _, file := range r.Files {
+		if file.Status == FileWritten {
+			total += file.Bytes
+		}
+	}
+	return total
+}
+
+// ResultReporter is implemented by collectors that report the outcome of the
+// files they handled. Collectors that forward templates to other ones should
+// also include the results of the next collectors.
+type ResultReporter interface {
+
+	// Results returns the outcome of the files handled in the current run
+	Results() []FileResult
+}
+
+// CollectorResults returns the results reported by the collector, if it is a
+// ResultReporter, nil otherwise.
+func CollectorResults(collector Collector) []FileResult {
+	if reporter, ok := collector.(ResultReporter); ok {
+		return reporter.Results()
+	}
+	return nil
+}
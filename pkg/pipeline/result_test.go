@@ -0,0 +1,108 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult_Count(t *testing.T) {
+	result := &Result{
+		Files: []FileResult{
+			{Path: "file1", Status: FileWritten, Bytes: 10},
+			{Path: "file2", Status: FileWritten, Bytes: 20},
+			{Path: "file3", Status: FileUnchanged, Bytes: 30},
+			{Path: "file4", Status: FileRemoved},
+		},
+	}
+
+	assert.Equal(t, 2, result.Count(FileWritten))
+	assert.Equal(t, 1, result.Count(FileUnchanged))
+	assert.Equal(t, 1, result.Count(FileRemoved))
+	assert.Equal(t, 0, result.Count(FileSkipped))
+	assert.Equal(t, int64(30), result.BytesWritten())
+}
+
+func TestCollectorResults(t *testing.T) {
+	assert.Nil(t, CollectorResults(&collectorMock{}))
+
+	results := []FileResult{{Path: "some-path", Status: FileSkipped}}
+	assert.Equal(t, results, CollectorResults(&reportingCollector{results: results}))
+}
+
+func Test_pipeline_ProcessWithResult(t *testing.T) {
+	tests := []struct {
+		name          string
+		templates     map[string]string
+		wantTemplates []TemplateResult
+		wantErr       string
+	}{
+		{
+			name: "Should return outcome of templates and files",
+			templates: map[string]string{
+				"file1": "some-content",
+				"file2": "{{ .Values.name }}",
+			},
+			wantTemplates: []TemplateResult{
+				{Path: "file1", Bytes: 12},
+				{Path: "file2", Bytes: 10},
+			},
+		},
+		{
+			name: "Should return outcome up to the failing template",
+			templates: map[string]string{
+				"file1": "some-content",
+				"file2": "{{ .Values.name }",
+				"file3": "some-other-content",
+			},
+			wantTemplates: []TemplateResult{
+				{Path: "file1", Bytes: 12},
+				{Path: "file2"},
+			},
+			wantErr: "template: file2:1: unexpected \"}\" in operand",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := &reportingCollector{
+				results: []FileResult{{Path: "some-path", Status: FileWritten, Bytes: 42}},
+			}
+			p := &pipeline{
+				functions:        map[string]any{"dummy": func() string { return "" }},
+				templateProvider: newMapTemplateProvider(tt.templates),
+				collector:        collector,
+			}
+
+			got, err := p.ProcessWithResult(map[string]interface{}{
+				"Values": map[string]interface{}{"name": "some-value"},
+			})
+
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, got.Templates, len(tt.wantTemplates))
+			for i, want := range tt.wantTemplates {
+				assert.Equal(t, want.Path, got.Templates[i].Path)
+				assert.Equal(t, want.Bytes, got.Templates[i].Bytes)
+				assert.Greater(t, got.Templates[i].Duration.Nanoseconds(), int64(0))
+				assert.LessOrEqual(t, got.Templates[i].Duration, got.Duration)
+			}
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, got.Templates[len(got.Templates)-1].Err, tt.wantErr)
+			}
+			assert.Equal(t, collector.results, got.Files)
+		})
+	}
+}
+
+type reportingCollector struct {
+	discardCollector
+
+	results []FileResult
+}
+
+func (c *reportingCollector) Results() []FileResult {
+	return c.results
+}
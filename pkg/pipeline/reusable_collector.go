@@ -0,0 +1,23 @@
+package pipeline
+
+// ReusableCollector is implemented by collectors that keep state during a run
+// (i.e. the files written), or that forward the templates to other
+// collectors: NewRun returns a copy of the collector with a new state, and
+// with the next collectors in the chain replaced by their own copies. The
+// pipeline uses a new copy at each run, so that it can be processed multiple
+// times, also concurrently.
+type ReusableCollector interface {
+	Collector
+
+	// NewRun returns a copy of the collector to use for a single run
+	NewRun() Collector
+}
+
+// NewCollectorRun returns the collector to use for a run: a new copy if the
+// specified collector is reusable, the collector itself otherwise.
+func NewCollectorRun(collector Collector) Collector {
+	if reusableCollector, ok := collector.(ReusableCollector); ok {
+		return reusableCollector.NewRun()
+	}
+	return collector
+}
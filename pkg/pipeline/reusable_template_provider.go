@@ -0,0 +1,22 @@
+package pipeline
+
+// ReusableTemplateProvider is implemented by template providers that can be
+// iterated more than once: Reopen returns a new provider, independent from the
+// original one, over the same templates. The pipeline reopens the provider at
+// each run, so that it can be processed multiple times, also concurrently.
+type ReusableTemplateProvider interface {
+	TemplateProvider
+
+	// Reopen returns a new provider that iterates over all the templates from
+	// the beginning.
+	Reopen() (TemplateProvider, error)
+}
+
+// ReopenTemplateProvider returns the provider to use for a run: a reopened
+// one if the specified provider is reusable, the provider itself otherwise.
+func ReopenTemplateProvider(provider TemplateProvider) (TemplateProvider, error) {
+	if reusableProvider, ok := provider.(ReusableTemplateProvider); ok {
+		return reusableProvider.Reopen()
+	}
+	return provider, nil
+}
@@ -0,0 +1,125 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReopenTemplateProvider(t *testing.T) {
+	notReusable := &templateProviderMock{}
+	got, err := ReopenTemplateProvider(notReusable)
+	assert.NoError(t, err)
+	assert.Same(t, notReusable, got)
+
+	reusable := newMapTemplateProvider(map[string]string{"some-path": "some-content"})
+	got, err = ReopenTemplateProvider(reusable)
+	assert.NoError(t, err)
+	assert.NotSame(t, reusable, got)
+	assert.Equal(t, reusable, got)
+
+	failing := &failingReusableProvider{err: errors.New("some-reopen-error")}
+	got, err = ReopenTemplateProvider(failing)
+	assertutils.AssertEqualErrors(t, errors.New("some-reopen-error"), err)
+	assert.Nil(t, got)
+}
+
+func TestNewCollectorRun(t *testing.T) {
+	notReusable := &collectorMock{}
+	assert.Same(t, notReusable, NewCollectorRun(notReusable))
+
+	reusable := &recordingCollector{}
+	got := NewCollectorRun(reusable)
+	assert.NotSame(t, reusable, got)
+	assert.IsType(t, &recordingCollector{}, got)
+
+	assert.Nil(t, NewCollectorRun(nil))
+}
+
+func Test_pipeline_Process_ShouldBeReusable(t *testing.T) {
+	collector := &recordingCollector{}
+	p, err := NewPipelineBuilder().
+		WithFunctions(template.FuncMap{"upper": strings.ToUpper}).
+		WithTemplateProvider(newMapTemplateProvider(map[string]string{
+			"file1": "{{ upper .Values.name }}-1",
+			"file2": "{{ template \"common\" . }}-2",
+		})).
+		WithNamedTemplatesProvider(newMapTemplateProvider(map[string]string{
+			"common": "{{ define \"common\" }}{{ .Values.name }}{{ end }}",
+		})).
+		WithCollector(collector).
+		WithTemplateCache(NewTemplateCache()).
+		Build()
+	assert.NoError(t, err)
+
+	const runs = 10
+	var wg sync.WaitGroup
+	errs := make([]error, runs)
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = p.Process(map[string]interface{}{
+				"Values": map[string]interface{}{"name": fmt.Sprintf("run%d", i)},
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < runs; i++ {
+		assert.NoError(t, errs[i])
+	}
+	assert.Len(t, collector.completedRuns, runs)
+	for _, run := range collector.completedRuns {
+		assert.Len(t, run.contents, 2)
+		name := strings.TrimSuffix(run.contents["file2"], "-2")
+		assert.Equal(t, strings.ToUpper(name)+"-1", run.contents["file1"])
+	}
+}
+
+// recordingCollector stores the content collected in each run, and the
+// completed runs in the original instance
+type recordingCollector struct {
+	mutex         sync.Mutex
+	original      *recordingCollector
+	contents      map[string]string
+	completedRuns []*recordingCollector
+}
+
+func (c *recordingCollector) Collect(args *Template) error {
+	var content strings.Builder
+	err := RenderReader(args.Reader)(&content)
+	c.contents[args.Path] = content.String()
+	return err
+}
+
+func (c *recordingCollector) OnPipelineCompleted() error {
+	c.original.mutex.Lock()
+	defer c.original.mutex.Unlock()
+
+	c.original.completedRuns = append(c.original.completedRuns, c)
+	return nil
+}
+
+func (c *recordingCollector) NewRun() Collector {
+	return &recordingCollector{
+		original: c,
+		contents: make(map[string]string),
+	}
+}
+
+type failingReusableProvider struct {
+	templateProviderMock
+
+	err error
+}
+
+func (p *failingReusableProvider) Reopen() (TemplateProvider, error) {
+	return nil, p.err
+}
@@ -0,0 +1,50 @@
+package pipeline
+
+import (
+	"bytes"
+	"io"
+)
+
+// RenderFunc writes the content of a template to the specified writer
+type RenderFunc func(w io.Writer) error
+
+// StreamingCollector is implemented by collectors that can receive the content
+// of the templates as a stream, rendered directly into a writer they provide,
+// instead of reading it from Template.Reader, so that the generated files
+// don't need to be kept in memory.
+type StreamingCollector interface {
+	Collector
+
+	// CollectStream collects the template described by args (Reader is not
+	// set); render writes the content of the template and it should be invoked
+	// at most once, with the writer the content should be written to.
+	CollectStream(args *Template, render RenderFunc) error
+}
+
+// CollectStream passes the template to the collector: if it is a
+// StreamingCollector the content is streamed to it, otherwise it is rendered
+// into a buffer and set as Template.Reader.
+func CollectStream(collector Collector, args *Template, render RenderFunc) error {
+	if streamingCollector, ok := collector.(StreamingCollector); ok {
+		return streamingCollector.CollectStream(args, render)
+	}
+
+	var buffer bytes.Buffer
+	err := render(&buffer)
+	if err != nil {
+		return err
+	}
+
+	tpl := *args
+	tpl.Reader = io.NopCloser(&buffer)
+	return collector.Collect(&tpl)
+}
+
+// RenderReader returns a RenderFunc that copies the content of the reader, it
+// can be used by streaming collectors to implement Collect.
+func RenderReader(reader io.Reader) RenderFunc {
+	return func(w io.Writer) error {
+		_, err := io.Copy(w, reader)
+		return err
+	}
+}
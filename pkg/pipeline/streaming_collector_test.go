@@ -0,0 +1,99 @@
+package pipeline
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pasdam/go-io-utilx/pkg/ioutilx"
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCollectStream(t *testing.T) {
+	tests := []struct {
+		name       string
+		streaming  bool
+		renderErr  error
+		collectErr error
+		wantErr    error
+	}{
+		{
+			name: "Should buffer content for collectors that don't support streaming",
+		},
+		{
+			name:      "Should stream content to streaming collectors",
+			streaming: true,
+		},
+		{
+			name:      "Should propagate render error and not collect the template",
+			renderErr: errors.New("some-render-error"),
+			wantErr:   errors.New("some-render-error"),
+		},
+		{
+			name:       "Should propagate collector error",
+			collectErr: errors.New("some-collect-error"),
+			wantErr:    errors.New("some-collect-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := &Template{Name: "some-name", Path: "some-path"}
+			render := func(w io.Writer) error {
+				if tt.renderErr != nil {
+					return tt.renderErr
+				}
+				_, err := io.WriteString(w, "some-content")
+				return err
+			}
+
+			var err error
+			if tt.streaming {
+				collector := &streamingCollectorMock{}
+				err = CollectStream(collector, args, render)
+
+				assert.Same(t, args, collector.args)
+				assert.Equal(t, "some-content", collector.content.String())
+			} else {
+				collector := &collectorMock{}
+				collector.On("Collect", mock.Anything).Return(tt.collectErr)
+
+				err = CollectStream(collector, args, render)
+
+				if tt.renderErr == nil {
+					got := collector.Calls[0].Arguments.Get(0).(*Template)
+					assert.Equal(t, args.Name, got.Name)
+					assert.Equal(t, args.Path, got.Path)
+					assert.Equal(t, "some-content", ioutilx.ReaderToString(got.Reader))
+					assert.Nil(t, args.Reader)
+				} else {
+					collector.AssertNotCalled(t, "Collect", mock.Anything)
+				}
+			}
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestRenderReader(t *testing.T) {
+	var buffer strings.Builder
+
+	err := RenderReader(strings.NewReader("some-content"))(&buffer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "some-content", buffer.String())
+}
+
+type streamingCollectorMock struct {
+	collectorMock
+
+	args    *Template
+	content strings.Builder
+}
+
+func (m *streamingCollectorMock) CollectStream(args *Template, render RenderFunc) error {
+	m.args = args
+	return render(&m.content)
+}
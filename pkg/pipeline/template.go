@@ -1,6 +1,10 @@
 package pipeline
 
-import "io"
+import (
+	"context"
+	"io"
+	"io/fs"
+)
 
 type Template struct {
 	// Name is the unique identifier for this template, used to reference it from other templates.
@@ -10,6 +14,48 @@ type Template struct {
 	// Path is the file path of the template.
 	Path string
 
-	// Reader provides access to the template content.
+	// Source is the path of the main template that generated this one, it
+	// differs from Path for the templates created by collectors (i.e. the files
+	// extracted by the splitter). It is set by the pipeline, collectors should
+	// use Path if it is empty.
+	Source string
+
+	// Origin describes where the provider read the template from, if it
+	// combines multiple sources (i.e. the layer of an overlay provider). It is
+	// propagated to the templates created from this one.
+	Origin string
+
+	// Mode contains the permission bits of the template file, if known by the
+	// provider; collectors can use it for the generated files (0 means the
+	// default permissions of the collector).
+	Mode fs.FileMode
+
+	// Reader provides access to the template content. It is not set for
+	// templates passed to StreamingCollector.CollectStream, as the content is
+	// rendered directly into the collector writer.
 	Reader io.ReadCloser
+
+	ctx context.Context
+}
+
+// Context returns the context of the run processing the template, that
+// carries i.e. the current tracing span. It returns context.Background() if
+// not set.
+func (t *Template) Context() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
+}
+
+// WithContext returns a shallow copy of the template with the specified
+// context, collectors use it to propagate the context to the templates they
+// forward. The context must be non-nil.
+func (t *Template) WithContext(ctx context.Context) *Template {
+	if ctx == nil {
+		panic("nil context")
+	}
+	copy := *t
+	copy.ctx = ctx
+	return &copy
 }
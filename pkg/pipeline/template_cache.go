@@ -0,0 +1,110 @@
+package pipeline
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"text/template"
+)
+
+// TemplateCache stores compiled templates, keyed by a hash of their content,
+// so that templates processed more than once (i.e. by multiple Process calls)
+// are parsed only the first time. The templates are still read from the
+// template provider at every run, as the keys depend on their content, so that
+// changed templates are parsed again.
+// A cache can be shared by multiple pipelines only if they are configured with
+// the same functions, as they are bound to the compiled templates.
+type TemplateCache interface {
+
+	// Get returns the template stored with the specified key, if any
+	Get(key string) (*template.Template, bool)
+
+	// Set stores the template with the specified key
+	Set(key string, tpl *template.Template)
+}
+
+// defaultTemplateCacheSize is the default maximum number of templates of an
+// in memory cache
+const defaultTemplateCacheSize = 1024
+
+type TemplateCacheOptions struct {
+	MaxEntries int // Maximum number of templates kept in the cache, the least recently used ones are evicted; defaults to 1024
+}
+
+// templateCacheEntry is an element of the list of a memoryTemplateCache
+type templateCacheEntry struct {
+	key string
+	tpl *template.Template
+}
+
+type memoryTemplateCache struct {
+	mutex      sync.Mutex
+	maxEntries int
+	entries    *list.List               // most recently used first
+	elements   map[string]*list.Element // elements of entries by key
+}
+
+// NewTemplateCache returns an in memory TemplateCache, safe for concurrent use,
+// that keeps up to 1024 templates; see NewTemplateCacheWithOpts
+func NewTemplateCache() TemplateCache {
+	return NewTemplateCacheWithOpts(TemplateCacheOptions{})
+}
+
+// NewTemplateCacheWithOpts returns an in memory TemplateCache, safe for
+// concurrent use, that keeps up to MaxEntries templates, evicting the least
+// recently used ones. Each run of a pipeline stores a template for its common
+// templates and one for each main template, so the size should be at least
+// the number of templates of the pipelines that share the cache.
+func NewTemplateCacheWithOpts(opts TemplateCacheOptions) TemplateCache {
+	maxEntries := opts.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultTemplateCacheSize
+	}
+	return &memoryTemplateCache{
+		maxEntries: maxEntries,
+		entries:    list.New(),
+		elements:   make(map[string]*list.Element),
+	}
+}
+
+func (c *memoryTemplateCache) Get(key string) (*template.Template, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.elements[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(element)
+	return element.Value.(*templateCacheEntry).tpl, true
+}
+
+func (c *memoryTemplateCache) Set(key string, tpl *template.Template) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.elements[key]; ok {
+		element.Value.(*templateCacheEntry).tpl = tpl
+		c.entries.MoveToFront(element)
+		return
+	}
+
+	c.elements[key] = c.entries.PushFront(&templateCacheEntry{key: key, tpl: tpl})
+	for c.entries.Len() > c.maxEntries {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.elements, oldest.Value.(*templateCacheEntry).key)
+	}
+}
+
+// cacheKey returns the hash of the specified parts, that are separated so that
+// different splits of the same content produce different keys.
+func cacheKey(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
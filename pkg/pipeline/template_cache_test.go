@@ -0,0 +1,93 @@
+package pipeline
+
+import (
+	"fmt"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_memoryTemplateCache(t *testing.T) {
+	a := template.New("a")
+	b := template.New("b")
+	c := template.New("c")
+	tests := []struct {
+		name     string
+		actions  func(cache TemplateCache)
+		wantKeys []string
+		missing  []string
+	}{
+		{
+			name: "Should return the stored templates",
+			actions: func(cache TemplateCache) {
+				cache.Set("a", a)
+				cache.Set("b", b)
+			},
+			wantKeys: []string{"a", "b"},
+			missing:  []string{"c"},
+		},
+		{
+			name: "Should evict the least recently stored template",
+			actions: func(cache TemplateCache) {
+				cache.Set("a", a)
+				cache.Set("b", b)
+				cache.Set("c", c)
+			},
+			wantKeys: []string{"b", "c"},
+			missing:  []string{"a"},
+		},
+		{
+			name: "Should evict the least recently read template",
+			actions: func(cache TemplateCache) {
+				cache.Set("a", a)
+				cache.Set("b", b)
+				cache.Get("a")
+				cache.Set("c", c)
+			},
+			wantKeys: []string{"a", "c"},
+			missing:  []string{"b"},
+		},
+		{
+			name: "Should replace a stored template without evicting others",
+			actions: func(cache TemplateCache) {
+				cache.Set("a", c)
+				cache.Set("b", b)
+				cache.Set("a", a)
+			},
+			wantKeys: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewTemplateCacheWithOpts(TemplateCacheOptions{MaxEntries: 2})
+
+			tt.actions(cache)
+
+			for _, key := range tt.wantKeys {
+				got, ok := cache.Get(key)
+				assert.True(t, ok, key)
+				assert.Equal(t, key, got.Name())
+			}
+			for _, key := range tt.missing {
+				got, ok := cache.Get(key)
+				assert.False(t, ok, key)
+				assert.Nil(t, got)
+			}
+		})
+	}
+}
+
+func TestNewTemplateCache_ShouldKeepTheDefaultNumberOfTemplates(t *testing.T) {
+	cache := NewTemplateCache()
+
+	for i := 0; i <= defaultTemplateCacheSize; i++ {
+		cache.Set(fmt.Sprint(i), template.New(fmt.Sprint(i)))
+	}
+
+	_, ok := cache.Get("0")
+	assert.False(t, ok)
+	_, ok = cache.Get("1")
+	assert.True(t, ok)
+	assert.Equal(t, defaultTemplateCacheSize, cache.(*memoryTemplateCache).entries.Len())
+}
@@ -0,0 +1,38 @@
+package pipeline
+
+import (
+	"text/template"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/templates"
+)
+
+var _parseTemplate = templates.ParseNamedTemplate
+
+// templateParser parses the main templates of a run, reusing the compiled ones
+// from the cache, if one is configured.
+type templateParser struct {
+	functions        template.FuncMap
+	templateAwareFns templates.TemplateAwareFuncMap
+	baseTemplate     *template.Template
+	baseKey          string
+	cache            TemplateCache
+}
+
+func (p *templateParser) parse(name string, content string) (*template.Template, error) {
+	if p.cache == nil {
+		return _parseTemplate(name, content, p.functions, p.templateAwareFns, p.baseTemplate)
+	}
+
+	key := cacheKey("template", p.baseKey, name, content)
+	if tpl, ok := p.cache.Get(key); ok {
+		return tpl, nil
+	}
+
+	tpl, err := _parseTemplate(name, content, p.functions, p.templateAwareFns, p.baseTemplate)
+	if err != nil {
+		return nil, err
+	}
+	p.cache.Set(key, tpl)
+
+	return tpl, nil
+}
@@ -0,0 +1,86 @@
+package pipeline
+
+import (
+	"errors"
+	"testing"
+	"text/template"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/templates"
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_templateParser_parse(t *testing.T) {
+	tests := []struct {
+		name          string
+		withCache     bool
+		contents      []string
+		parseErr      error
+		wantParseCall int
+		wantSame      bool
+		wantErr       error
+	}{
+		{
+			name:          "Should parse templates every time if no cache is configured",
+			contents:      []string{"some-content", "some-content"},
+			wantParseCall: 2,
+		},
+		{
+			name:          "Should parse template only once if the cache is configured",
+			withCache:     true,
+			contents:      []string{"some-content", "some-content"},
+			wantParseCall: 1,
+			wantSame:      true,
+		},
+		{
+			name:          "Should parse template again if the content changes",
+			withCache:     true,
+			contents:      []string{"some-content", "some-other-content"},
+			wantParseCall: 2,
+		},
+		{
+			name:          "Should propagate parsing errors and not cache them",
+			withCache:     true,
+			contents:      []string{"some-content", "some-content"},
+			parseErr:      errors.New("some-parse-error"),
+			wantParseCall: 2,
+			wantErr:       errors.New("some-parse-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &templateParser{
+				functions: template.FuncMap{},
+			}
+			if tt.withCache {
+				parser.cache = NewTemplateCache()
+			}
+			parseCalls := 0
+			originalValue := _parseTemplate
+			_parseTemplate = func(name string, content string, _ template.FuncMap, _ templates.TemplateAwareFuncMap, _ *template.Template) (*template.Template, error) {
+				parseCalls++
+				if tt.parseErr != nil {
+					return nil, tt.parseErr
+				}
+				return template.New(name).Parse(content)
+			}
+			t.Cleanup(func() { _parseTemplate = originalValue })
+
+			got := make([]*template.Template, 0, len(tt.contents))
+			for _, content := range tt.contents {
+				tpl, err := parser.parse("some-name", content)
+				assertutils.AssertEqualErrors(t, tt.wantErr, err)
+				got = append(got, tpl)
+			}
+
+			assert.Equal(t, tt.wantParseCall, parseCalls)
+			if tt.wantErr == nil {
+				if tt.wantSame {
+					assert.Same(t, got[0], got[1])
+				} else {
+					assert.NotSame(t, got[0], got[1])
+				}
+			}
+		})
+	}
+}
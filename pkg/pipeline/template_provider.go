@@ -1,5 +1,22 @@
 package pipeline
 
+import "io"
+
+// TemplateProvider provides the templates to process, NextTemplate returns
+// io.EOF when there are no more templates. The pipeline processes the
+// templates in the order they are returned, providers should return them in a
+// deterministic order (i.e. sorted by path, like the providers of the
+// templateproviders package). Providers that hold resources, i.e. open files,
+// can implement io.Closer: the pipeline closes the provider of each run when
+// the run completes, also if it fails.
 type TemplateProvider interface {
 	NextTemplate() (*Template, error)
 }
+
+// CloseTemplateProvider closes the provider, if it implements io.Closer
+func CloseTemplateProvider(provider TemplateProvider) error {
+	if closer, ok := provider.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
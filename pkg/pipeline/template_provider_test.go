@@ -0,0 +1,89 @@
+package pipeline
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloseTemplateProvider(t *testing.T) {
+	assert.NoError(t, CloseTemplateProvider(&templateProviderMock{}))
+
+	closing := &closingProvider{}
+	assert.NoError(t, CloseTemplateProvider(closing))
+	assert.Equal(t, 1, closing.closed)
+
+	failing := &closingProvider{err: errors.New("some-close-error")}
+	assertutils.AssertEqualErrors(t, errors.New("some-close-error"), CloseTemplateProvider(failing))
+	assert.Equal(t, 1, failing.closed)
+}
+
+func Test_pipeline_Process_ShouldCloseProviders(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		closeErr error
+		wantErr  error
+	}{
+		{
+			name:    "Should close the providers when the run completes",
+			content: "{{ upper .Values.name }}",
+		},
+		{
+			name:    "Should close the providers when the run fails",
+			content: "{{ upper .Values.name ",
+			wantErr: errors.New("template: some-path:1: unclosed action"),
+		},
+		{
+			name:     "Should return the error of close if the run completes",
+			content:  "{{ upper .Values.name }}",
+			closeErr: errors.New("some-close-error"),
+			wantErr:  errors.New("some-close-error"),
+		},
+		{
+			name:     "Should return the error of the run if close fails too",
+			content:  "{{ upper .Values.name ",
+			closeErr: errors.New("some-close-error"),
+			wantErr:  errors.New("template: some-path:1: unclosed action"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &closingProvider{err: tt.closeErr}
+			provider.On("NextTemplate").Return(&Template{Path: "some-path", Reader: io.NopCloser(strings.NewReader(tt.content))}, nil).Once()
+			provider.On("NextTemplate").Return(nil, io.EOF)
+			namedProvider := &closingProvider{}
+			namedProvider.On("NextTemplate").Return(nil, io.EOF)
+			p, err := NewPipelineBuilder().
+				WithFunctions(template.FuncMap{"upper": strings.ToUpper}).
+				WithTemplateProvider(provider).
+				WithNamedTemplatesProvider(namedProvider).
+				WithCollector(&recordingCollector{}).
+				Build()
+			assert.NoError(t, err)
+
+			err = p.Process(map[string]interface{}{"Values": map[string]interface{}{"name": "some-name"}})
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, 1, provider.closed)
+			assert.Equal(t, 1, namedProvider.closed)
+		})
+	}
+}
+
+type closingProvider struct {
+	templateProviderMock
+
+	closed int
+	err    error
+}
+
+func (p *closingProvider) Close() error {
+	p.closed++
+	return p.err
+}
@@ -0,0 +1,24 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type contextKey struct{}
+
+func TestTemplate_Context(t *testing.T) {
+	tpl := &Template{Path: "some-path"}
+	assert.Equal(t, context.Background(), tpl.Context())
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "some-value")
+	got := tpl.WithContext(ctx)
+
+	assert.NotSame(t, tpl, got)
+	assert.Equal(t, "some-path", got.Path)
+	assert.Equal(t, ctx, got.Context())
+	assert.Equal(t, context.Background(), tpl.Context())
+	assert.Panics(t, func() { tpl.WithContext(nil) })
+}
@@ -0,0 +1,20 @@
+package preprocessors
+
+import (
+	"fmt"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// Computed returns a preprocessor that sets the value returned by fn at the
+// dot-separated path (i.e. "Values.fullName"), creating the missing maps along
+// it. The input data is not modified.
+func Computed(path string, fn func(data map[string]interface{}) (interface{}, error)) pipeline.DataPreprocessor {
+	return func(data map[string]interface{}) (map[string]interface{}, error) {
+		value, err := fn(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compute %s: %w", path, err)
+		}
+		return setPath(data, path, value)
+	}
+}
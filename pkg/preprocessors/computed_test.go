@@ -0,0 +1,64 @@
+package preprocessors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputed(t *testing.T) {
+	data := map[string]interface{}{
+		"Values": map[string]interface{}{"name": "some-name", "namespace": "some-namespace"},
+	}
+	tests := []struct {
+		name    string
+		path    string
+		fn      func(map[string]interface{}) (interface{}, error)
+		want    map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "Should set computed value",
+			path: "Values.fullName",
+			fn: func(data map[string]interface{}) (interface{}, error) {
+				values := data["Values"].(map[string]interface{})
+				return fmt.Sprintf("%s/%s", values["namespace"], values["name"]), nil
+			},
+			want: map[string]interface{}{
+				"Values": map[string]interface{}{
+					"name":      "some-name",
+					"namespace": "some-namespace",
+					"fullName":  "some-namespace/some-name",
+				},
+			},
+		},
+		{
+			name: "Should return error if computation fails",
+			path: "Values.fullName",
+			fn: func(data map[string]interface{}) (interface{}, error) {
+				return nil, errors.New("some-error")
+			},
+			wantErr: errors.New("unable to compute Values.fullName: some-error"),
+		},
+		{
+			name: "Should return error if path is not valid",
+			path: "Values.name.other",
+			fn: func(data map[string]interface{}) (interface{}, error) {
+				return "some-value", nil
+			},
+			wantErr: errors.New("value at Values.name is not a map"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Computed(tt.path, tt.fn)(data)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+			assert.Len(t, data["Values"], 2)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package preprocessors
+
+import (
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/pasdam/go-template-map-loader/pkg/tm"
+)
+
+// Defaults returns a preprocessor that merges the data on top of the
+// specified defaults, nested maps are merged recursively. Neither the data nor
+// the defaults are modified.
+func Defaults(defaults map[string]interface{}) pipeline.DataPreprocessor {
+	return func(data map[string]interface{}) (map[string]interface{}, error) {
+		return tm.MergeMaps(defaults, data), nil
+	}
+}
@@ -0,0 +1,33 @@
+package preprocessors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaults(t *testing.T) {
+	defaults := map[string]interface{}{
+		"Values": map[string]interface{}{
+			"replicas": 1,
+			"image":    map[string]interface{}{"tag": "latest", "name": "some-image"},
+		},
+	}
+	data := map[string]interface{}{
+		"Values": map[string]interface{}{
+			"image": map[string]interface{}{"tag": "v1"},
+		},
+	}
+
+	got, err := Defaults(defaults)(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Values": map[string]interface{}{
+			"replicas": 1,
+			"image":    map[string]interface{}{"tag": "v1", "name": "some-image"},
+		},
+	}, got)
+	assert.Equal(t, map[string]interface{}{"tag": "latest", "name": "some-image"}, defaults["Values"].(map[string]interface{})["image"])
+	assert.Equal(t, map[string]interface{}{"tag": "v1"}, data["Values"].(map[string]interface{})["image"])
+}
@@ -0,0 +1,68 @@
+package preprocessors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitPath splits a dot-separated path (i.e. "Values.service.name") into its
+// keys, an empty path has no keys and refers to the whole data
+func splitPath(path string) []string {
+	if len(path) == 0 {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// lookupPath returns the value at the specified path in data
+func lookupPath(data map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = data
+	for _, key := range splitPath(path) {
+		current, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok = current[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// setPath returns a copy of data with the value at the specified path, the
+// maps along the path are copied so that data is not modified, and created
+// if missing
+func setPath(data map[string]interface{}, path string, value interface{}) (map[string]interface{}, error) {
+	keys := splitPath(path)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	out := copyMap(data)
+	current := out
+	for i, key := range keys[:len(keys)-1] {
+		next, ok := current[key]
+		if !ok || next == nil {
+			next = map[string]interface{}{}
+		}
+		nextMap, ok := next.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("value at %s is not a map", strings.Join(keys[:i+1], "."))
+		}
+		nextMap = copyMap(nextMap)
+		current[key] = nextMap
+		current = nextMap
+	}
+	current[keys[len(keys)-1]] = value
+
+	return out, nil
+}
+
+func copyMap(data map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		out[key] = value
+	}
+	return out
+}
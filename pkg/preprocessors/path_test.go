@@ -0,0 +1,90 @@
+package preprocessors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_lookupPath(t *testing.T) {
+	data := map[string]interface{}{
+		"Values": map[string]interface{}{
+			"name": "some-name",
+			"nil":  nil,
+		},
+	}
+	tests := []struct {
+		name   string
+		path   string
+		want   interface{}
+		wantOk bool
+	}{
+		{name: "Should return whole data for empty path", path: "", want: data, wantOk: true},
+		{name: "Should return nested value", path: "Values.name", want: "some-name", wantOk: true},
+		{name: "Should return nil value", path: "Values.nil", want: nil, wantOk: true},
+		{name: "Should not find missing key", path: "Values.missing", wantOk: false},
+		{name: "Should not find key in a non-map", path: "Values.name.other", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := lookupPath(data, tt.path)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOk, gotOk)
+		})
+	}
+}
+
+func Test_setPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]interface{}
+		path    string
+		want    map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "Should set top level value",
+			data: map[string]interface{}{"a": 1},
+			path: "b",
+			want: map[string]interface{}{"a": 1, "b": "some-value"},
+		},
+		{
+			name: "Should set nested value",
+			data: map[string]interface{}{"a": map[string]interface{}{"b": 1}},
+			path: "a.c",
+			want: map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": "some-value"}},
+		},
+		{
+			name: "Should create missing maps",
+			data: map[string]interface{}{"a": nil},
+			path: "a.b.c",
+			want: map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": "some-value"}}},
+		},
+		{
+			name:    "Should return error if an intermediate value is not a map",
+			data:    map[string]interface{}{"a": map[string]interface{}{"b": 1}},
+			path:    "a.b.c",
+			wantErr: errors.New("value at a.b is not a map"),
+		},
+		{
+			name:    "Should return error if path is empty",
+			data:    map[string]interface{}{},
+			path:    "",
+			wantErr: errors.New("empty path"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := copyMap(tt.data)
+
+			got, err := setPath(tt.data, tt.path, "some-value")
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, original, tt.data)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package preprocessors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// Required returns a preprocessor that fails if any of the dot-separated paths
+// (i.e. "Values.service.name") is missing or nil in the data, reporting all the
+// missing ones.
+func Required(paths ...string) pipeline.DataPreprocessor {
+	return func(data map[string]interface{}) (map[string]interface{}, error) {
+		missing := make([]string, 0)
+		for _, path := range paths {
+			value, ok := lookupPath(data, path)
+			if !ok || value == nil {
+				missing = append(missing, path)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("missing required values: %s", strings.Join(missing, ", "))
+		}
+		return data, nil
+	}
+}
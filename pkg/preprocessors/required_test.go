@@ -0,0 +1,45 @@
+package preprocessors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequired(t *testing.T) {
+	data := map[string]interface{}{
+		"Values": map[string]interface{}{
+			"name": "some-name",
+			"nil":  nil,
+		},
+	}
+	tests := []struct {
+		name    string
+		paths   []string
+		wantErr error
+	}{
+		{
+			name:  "Should accept data with all the required values",
+			paths: []string{"Values", "Values.name"},
+		},
+		{
+			name:    "Should report all the missing values",
+			paths:   []string{"Values.name", "Values.missing", "Values.nil", "Other"},
+			wantErr: errors.New("missing required values: Values.missing, Values.nil, Other"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Required(tt.paths...)(data)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, data, got)
+			} else {
+				assert.Nil(t, got)
+			}
+		})
+	}
+}
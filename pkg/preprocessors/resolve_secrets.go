@@ -0,0 +1,91 @@
+package preprocessors
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// SecretResolver returns the value of the secret with the specified reference
+type SecretResolver func(ref string) (string, error)
+
+// EnvSecretResolver resolves the secrets from the environment variables, the
+// reference is the name of the variable, that must be set.
+func EnvSecretResolver(ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return value, nil
+}
+
+// ResolveSecrets returns a preprocessor that replaces the string values
+// starting with the prefix (i.e. "env:") with the secret resolved by the
+// resolver, passing it the rest of the string as reference. Values are
+// searched in nested maps and lists, and the input data is not modified. Map
+// keys are visited in sorted order, so if more secrets can't be resolved the
+// error always refers to the first one.
+func ResolveSecrets(prefix string, resolver SecretResolver) pipeline.DataPreprocessor {
+	return func(data map[string]interface{}) (map[string]interface{}, error) {
+		resolved, err := resolveSecrets(data, "", prefix, resolver)
+		if err != nil {
+			return nil, err
+		}
+		return resolved.(map[string]interface{}), nil
+	}
+}
+
+func resolveSecrets(value interface{}, path string, prefix string, resolver SecretResolver) (interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		// keys are sorted, so that the secret that fails is always the same
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		out := make(map[string]interface{}, len(value))
+		for _, key := range keys {
+			resolved, err := resolveSecrets(value[key], joinPath(path, key), prefix, resolver)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = resolved
+		}
+		return out, nil
+
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, item := range value {
+			resolved, err := resolveSecrets(item, fmt.Sprintf("%s[%d]", path, i), prefix, resolver)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+
+	case string:
+		if !strings.HasPrefix(value, prefix) {
+			return value, nil
+		}
+		secret, err := resolver(strings.TrimPrefix(value, prefix))
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve secret at %s: %w", path, err)
+		}
+		return secret, nil
+	}
+
+	return value, nil
+}
+
+func joinPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,89 @@
+package preprocessors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	resolver := func(ref string) (string, error) {
+		if ref == "missing" {
+			return "", errors.New("some-error")
+		}
+		return "resolved-" + ref, nil
+	}
+	tests := []struct {
+		name    string
+		data    map[string]interface{}
+		want    map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "Should resolve secrets in nested maps and lists",
+			data: map[string]interface{}{
+				"Values": map[string]interface{}{
+					"password": "secret:db-password",
+					"name":     "some-name",
+					"replicas": 2,
+					"tokens":   []interface{}{"secret:token-1", "some-token", map[string]interface{}{"key": "secret:token-2"}},
+				},
+			},
+			want: map[string]interface{}{
+				"Values": map[string]interface{}{
+					"password": "resolved-db-password",
+					"name":     "some-name",
+					"replicas": 2,
+					"tokens":   []interface{}{"resolved-token-1", "some-token", map[string]interface{}{"key": "resolved-token-2"}},
+				},
+			},
+		},
+		{
+			name: "Should report path of the secret that cannot be resolved",
+			data: map[string]interface{}{
+				"Values": map[string]interface{}{
+					"tokens": []interface{}{"some-token", "secret:missing"},
+				},
+			},
+			wantErr: errors.New("unable to resolve secret at Values.tokens[1]: some-error"),
+		},
+		{
+			name: "Should report the first secret in key order if more cannot be resolved",
+			data: map[string]interface{}{
+				"Values": map[string]interface{}{
+					"e": "secret:missing",
+					"b": map[string]interface{}{"y": "secret:missing", "x": "secret:missing"},
+					"a": "some-value",
+					"c": "secret:missing",
+					"d": "secret:missing",
+				},
+			},
+			wantErr: errors.New("unable to resolve secret at Values.b.x: some-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := copyMap(tt.data)
+
+			got, err := ResolveSecrets("secret:", resolver)(tt.data)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, original, tt.data)
+		})
+	}
+}
+
+func TestEnvSecretResolver(t *testing.T) {
+	t.Setenv("SOME_SECRET", "some-value")
+
+	got, err := EnvSecretResolver("SOME_SECRET")
+	assert.NoError(t, err)
+	assert.Equal(t, "some-value", got)
+
+	got, err = EnvSecretResolver("SOME_MISSING_SECRET_VARIABLE")
+	assert.Equal(t, errors.New("environment variable SOME_MISSING_SECRET_VARIABLE is not set"), err)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,29 @@
+package preprocessors
+
+import (
+	"fmt"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// Validate returns a preprocessor that validates the map at the dot-separated
+// path (i.e. "Values"), or the whole data if the path is empty, against the
+// JSON schema using the specified validator.
+func Validate(validator pipeline.ValuesValidator, path string, schemaJSON []byte) pipeline.DataPreprocessor {
+	return func(data map[string]interface{}) (map[string]interface{}, error) {
+		value, ok := lookupPath(data, path)
+		if !ok {
+			return nil, fmt.Errorf("no values to validate at %s", path)
+		}
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("value at %s is not a map", path)
+		}
+
+		err := validator.ValidateYaml(values, schemaJSON)
+		if err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+}
@@ -0,0 +1,78 @@
+package preprocessors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+type validatorMock struct {
+	values map[string]interface{}
+	schema []byte
+	err    error
+}
+
+func (m *validatorMock) ValidateYaml(values map[string]interface{}, schemaJSON []byte) error {
+	m.values = values
+	m.schema = schemaJSON
+	return m.err
+}
+
+func TestValidate(t *testing.T) {
+	values := map[string]interface{}{"name": "some-name"}
+	data := map[string]interface{}{"Values": values, "Other": "some-value"}
+	tests := []struct {
+		name          string
+		path          string
+		validatorErr  error
+		wantValidated map[string]interface{}
+		wantErr       error
+	}{
+		{
+			name:          "Should validate values at path",
+			path:          "Values",
+			wantValidated: values,
+		},
+		{
+			name:          "Should validate whole data if path is empty",
+			path:          "",
+			wantValidated: data,
+		},
+		{
+			name:          "Should propagate validation error",
+			path:          "Values",
+			validatorErr:  errors.New("some-validation-error"),
+			wantValidated: values,
+			wantErr:       errors.New("some-validation-error"),
+		},
+		{
+			name:    "Should return error if path is missing",
+			path:    "Missing",
+			wantErr: errors.New("no values to validate at Missing"),
+		},
+		{
+			name:    "Should return error if value at path is not a map",
+			path:    "Other",
+			wantErr: errors.New("value at Other is not a map"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &validatorMock{err: tt.validatorErr}
+			schema := []byte(`{"type": "object"}`)
+
+			got, err := Validate(validator, tt.path, schema)(data)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantValidated, validator.values)
+			if tt.wantErr == nil {
+				assert.Equal(t, data, got)
+				assert.Equal(t, schema, validator.schema)
+			} else {
+				assert.Nil(t, got)
+			}
+		})
+	}
+}
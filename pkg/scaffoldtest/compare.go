@@ -0,0 +1,122 @@
+package scaffoldtest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffContext is the number of unchanged lines around the changes in a diff
+const diffContext = 3
+
+// FileDiff is a mismatch between a golden file and the generated one
+type FileDiff struct {
+	Path string // Path of the file, relative to the golden folder
+	Diff string // Unified diff from the golden file to the generated one; empty if one of them is missing
+	Msg  string // Description of the mismatch
+}
+
+func (d *FileDiff) String() string {
+	if len(d.Diff) == 0 {
+		return fmt.Sprintf("%s: %s", d.Path, d.Msg)
+	}
+	return fmt.Sprintf("%s: %s\n%s", d.Path, d.Msg, d.Diff)
+}
+
+// Compare compares the files with the ones in goldenDir, and returns the
+// mismatches sorted by path: files with different content, files missing
+// from the output and files not expected.
+func Compare(goldenDir string, files []collectors.GeneratedFile) ([]FileDiff, error) {
+	golden, err := readGoldenFiles(goldenDir)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := make(map[string][]byte, len(files))
+	for _, file := range files {
+		generated[file.Path] = file.Content
+	}
+
+	paths := make([]string, 0, len(golden)+len(generated))
+	for path := range golden {
+		paths = append(paths, path)
+	}
+	for path := range generated {
+		if _, ok := golden[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	diffs := make([]FileDiff, 0)
+	for _, path := range paths {
+		expected, isExpected := golden[path]
+		actual, isGenerated := generated[path]
+		switch {
+		case !isGenerated:
+			diffs = append(diffs, FileDiff{Path: path, Msg: "golden file not generated"})
+		case !isExpected:
+			diffs = append(diffs, FileDiff{Path: path, Msg: "generated file has no golden file"})
+		case string(expected) != string(actual):
+			diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        splitLines(expected),
+				B:        splitLines(actual),
+				FromFile: "golden/" + path,
+				ToFile:   "generated/" + path,
+				Context:  diffContext,
+			})
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, FileDiff{Path: path, Diff: diff, Msg: "content mismatch"})
+		}
+	}
+	return diffs, nil
+}
+
+// splitLines splits the content in lines, keeping the line terminators; like
+// in git, a missing newline at the end of the content is marked
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n\\ No newline at end of file\n"
+	return lines
+}
+
+// readGoldenFiles returns the content of the files in goldenDir, by slash
+// separated path; a missing folder has no files
+func readGoldenFiles(goldenDir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(goldenDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == goldenDir && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(goldenDir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relativePath)] = content
+		return nil
+	})
+	return files, err
+}
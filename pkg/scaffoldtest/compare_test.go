@@ -0,0 +1,120 @@
+package scaffoldtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// readFiles reads the files in dir as generated files
+func readFiles(t *testing.T, dir string) []collectors.GeneratedFile {
+	golden, err := readGoldenFiles(dir)
+	assert.NoError(t, err)
+
+	files := make([]collectors.GeneratedFile, 0, len(golden))
+	for path, content := range golden {
+		files = append(files, collectors.GeneratedFile{Path: path, Content: content})
+	}
+	return files
+}
+
+func TestCompare(t *testing.T) {
+	goldenDir := filepath.Join("testdata", "golden")
+	mainGo, err := os.ReadFile(filepath.Join(goldenDir, "cmd", "main.go"))
+	assert.NoError(t, err)
+	readme, err := os.ReadFile(filepath.Join(goldenDir, "README.md"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		goldenDir string
+		files     []collectors.GeneratedFile
+		want      []FileDiff
+	}{
+		{
+			name:      "Should return no diff if the files match",
+			goldenDir: goldenDir,
+			files: []collectors.GeneratedFile{
+				{Path: "cmd/main.go", Content: mainGo},
+				{Path: "README.md", Content: readme},
+				{Path: "docs.md", Content: []byte("some-docs\n")},
+			},
+			want: []FileDiff{},
+		},
+		{
+			name:      "Should return diffs sorted by path",
+			goldenDir: goldenDir,
+			files: []collectors.GeneratedFile{
+				{Path: "go.mod", Content: []byte("module service\n")},
+				{Path: "docs.md", Content: []byte("some-docs")},
+				{Path: "README.md", Content: []byte("# service\n\nOwned by platform.\nSee docs.\n")},
+			},
+			want: []FileDiff{
+				{
+					Path: "README.md",
+					Msg:  "content mismatch",
+					Diff: "--- golden/README.md\n" +
+						"+++ generated/README.md\n" +
+						"@@ -1,3 +1,4 @@\n" +
+						" # service\n" +
+						" \n" +
+						" Owned by platform.\n" +
+						"+See docs.\n",
+				},
+				{Path: "cmd/main.go", Msg: "golden file not generated"},
+				{
+					Path: "docs.md",
+					Msg:  "content mismatch",
+					Diff: "--- golden/docs.md\n" +
+						"+++ generated/docs.md\n" +
+						"@@ -1 +1 @@\n" +
+						"-some-docs\n" +
+						"+some-docs\n" +
+						"\\ No newline at end of file\n",
+				},
+				{Path: "go.mod", Msg: "generated file has no golden file"},
+			},
+		},
+		{
+			name:      "Should report all files as unexpected if the golden folder doesn't exist",
+			goldenDir: filepath.Join("testdata", "missing"),
+			files:     []collectors.GeneratedFile{{Path: "README.md", Content: readme}},
+			want:      []FileDiff{{Path: "README.md", Msg: "generated file has no golden file"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compare(tt.goldenDir, tt.files)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileDiff_String(t *testing.T) {
+	tests := []struct {
+		name string
+		diff FileDiff
+		want string
+	}{
+		{
+			name: "Should describe missing file",
+			diff: FileDiff{Path: "some-path", Msg: "golden file not generated"},
+			want: "some-path: golden file not generated",
+		},
+		{
+			name: "Should include diff",
+			diff: FileDiff{Path: "some-path", Msg: "content mismatch", Diff: "some-diff"},
+			want: "some-path: content mismatch\nsome-diff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.diff.String())
+		})
+	}
+}
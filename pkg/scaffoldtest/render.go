@@ -0,0 +1,51 @@
+package scaffoldtest
+
+import (
+	"path/filepath"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/go-scaffold/go-sdk/v2/pkg/templateproviders"
+	"github.com/go-scaffold/go-sdk/v2/pkg/values"
+)
+
+const defaultTemplatesDir = "templates"
+
+// Render renders the scaffold in scaffoldDir, with the values loaded from its
+// manifest and values files, and returns the generated files
+func Render(scaffoldDir string, opts Options) (*collectors.MemoryCollector, error) {
+	loader := opts.Loader
+	if loader == nil {
+		loader = values.NewLoader()
+	}
+	data, err := loader.LoadYAMLs(scaffoldDir, opts.ValueFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	templatesDir := opts.TemplatesDir
+	if len(templatesDir) == 0 {
+		templatesDir = filepath.Join(scaffoldDir, defaultTemplatesDir)
+	}
+
+	output := collectors.NewMemoryCollector(nil)
+	var collector pipeline.Collector = output
+	if opts.Collector != nil {
+		collector = opts.Collector(output)
+	}
+
+	p, err := pipeline.NewPipelineBuilder().
+		WithTemplateProvider(templateproviders.NewFileSystemProvider(templatesDir, opts.Filter)).
+		WithCollector(collector).
+		WithFunctions(opts.Functions).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.Process(data)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,81 @@
+package scaffoldtest
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/go-scaffold/go-sdk/v2/pkg/values"
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	scaffoldDir := filepath.Join("testdata", "scaffold")
+	markdownFiles, err := filters.NewPatternFilter(true, `.*\.md`)
+	assert.NoError(t, err)
+	tests := []struct {
+		name        string
+		scaffoldDir string
+		opts        Options
+		want        map[string]string
+		wantErr     error
+	}{
+		{
+			name:        "Should render scaffold with its values",
+			scaffoldDir: scaffoldDir,
+			opts:        Options{Functions: testFunctions},
+			want: map[string]string{
+				"README.md":   "# service\n\nOwned by platform.\n",
+				"cmd/main.go": "package main\n\nfunc main() {\n\tprintln(\"SERVICE\")\n}\n",
+				"docs.md":     "some-docs\n",
+			},
+		},
+		{
+			name:        "Should render scaffold with options",
+			scaffoldDir: scaffoldDir,
+			opts: Options{
+				TemplatesDir: filepath.Join(scaffoldDir, "templates"),
+				ValueFiles:   []string{filepath.Join("testdata", "override.yaml")},
+				Loader:       values.NewLoader(),
+				Filter:       markdownFiles,
+				Functions:    testFunctions,
+				Collector: func(next pipeline.Collector) pipeline.Collector {
+					return collectors.NewTransformerCollector(filters.NewNoOpFilter(), func(path string, content []byte) ([]byte, error) {
+						return append(content, "<!-- generated -->\n"...), nil
+					}, next)
+				},
+			},
+			want: map[string]string{
+				"README.md": "# service\n\nOwned by payments.\n<!-- generated -->\n",
+				"docs.md":   "some-docs\n<!-- generated -->\n",
+			},
+		},
+		{
+			name:        "Should propagate error if values can't be loaded",
+			scaffoldDir: filepath.Join("testdata", "missing"),
+			opts:        Options{Functions: testFunctions},
+			wantErr:     errors.New("an error occurred while getting the manifest path: neither .yaml nor .yml file found for Manifest in testdata/missing"),
+		},
+		{
+			name:        "Should propagate error if the pipeline can't be built",
+			scaffoldDir: scaffoldDir,
+			wantErr:     errors.New("no functions specified in the context"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.scaffoldDir, tt.opts)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, got.Contents())
+			} else {
+				assert.Nil(t, got)
+			}
+		})
+	}
+}
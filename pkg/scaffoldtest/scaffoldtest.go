@@ -0,0 +1,103 @@
+// Package scaffoldtest provides golden-file tests for scaffolds: a scaffold is
+// rendered with its values, and the generated files are compared with a
+// checked-in tree of expected files.
+//
+// Running the tests with the SCAFFOLDTEST_UPDATE environment variable set to
+// true, i.e.
+//
+//	SCAFFOLDTEST_UPDATE=1 go test ./...
+//
+// (or with the Update option) writes the generated files in the golden
+// folder.
+package scaffoldtest
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/values"
+)
+
+// UpdateEnv is the environment variable that enables the update of the golden
+// files, if set to a true value (see strconv.ParseBool)
+const UpdateEnv = "SCAFFOLDTEST_UPDATE"
+
+var getenv = os.Getenv
+
+// TestingT is the subset of testing.TB used by Run
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+}
+
+type Options struct {
+	TemplatesDir string               // Directory of the templates; defaults to the "templates" folder of the scaffold
+	ValueFiles   []string             // Additional value files, applied after the values file of the scaffold
+	Loader       *values.Loader       // Loader of the manifest and values files; defaults to values.NewLoader()
+	Filter       filters.Filter       // Filter of the templates; defaults to all the files
+	Functions    template.FuncMap     // Functions available to the templates, at least one is required by the pipeline
+	Collector    collectors.ChainLink // Collector of the rendered templates, in front of the in-memory one, i.e. a splitter; optional
+	Update       bool                 // Whether to update the golden files, as if UpdateEnv was set, i.e. from a flag of the test package
+}
+
+// Run renders the scaffold in scaffoldDir and compares the generated files
+// with the ones in goldenDir, reporting an error with the diff of each
+// mismatching file. With the Update option (or the UpdateEnv environment
+// variable), the generated files are written in goldenDir before comparing
+// them, so that only the stale golden files, not generated any more, are
+// reported: they are not removed, see Update.
+func Run(t TestingT, scaffoldDir string, goldenDir string, opts Options) {
+	t.Helper()
+
+	output, err := Render(scaffoldDir, opts)
+	if err != nil {
+		t.Fatalf("unable to render scaffold %s: %s", scaffoldDir, err)
+		return
+	}
+
+	update := opts.Update || isUpdateEnabled()
+	if update {
+		err = Update(goldenDir, output.Files())
+		if err != nil {
+			t.Fatalf("unable to update golden files in %s: %s", goldenDir, err)
+			return
+		}
+	}
+
+	diffs, err := Compare(goldenDir, output.Files())
+	if err != nil {
+		t.Fatalf("unable to compare golden files in %s: %s", goldenDir, err)
+		return
+	}
+	for _, diff := range diffs {
+		t.Errorf("%s", diff.String())
+	}
+	switch {
+	case len(diffs) == 0:
+	case update:
+		t.Errorf("%d file(s) in %s are not generated by the scaffold %s, remove them:\n%s",
+			len(diffs), goldenDir, scaffoldDir, strings.Join(diffPaths(diffs), "\n"))
+	default:
+		t.Errorf("%d file(s) in %s don't match the scaffold %s, run the tests with %s=1 to refresh them:\n%s",
+			len(diffs), goldenDir, scaffoldDir, UpdateEnv, strings.Join(diffPaths(diffs), "\n"))
+	}
+}
+
+// isUpdateEnabled returns whether the UpdateEnv environment variable is true
+func isUpdateEnabled() bool {
+	update, _ := strconv.ParseBool(getenv(UpdateEnv))
+	return update
+}
+
+func diffPaths(diffs []FileDiff) []string {
+	paths := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		paths = append(paths, "  "+diff.Path)
+	}
+	return paths
+}
@@ -0,0 +1,128 @@
+package scaffoldtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testFunctions = template.FuncMap{"upper": strings.ToUpper}
+
+// recordingT records the errors reported by Run
+type recordingT struct {
+	errors []string
+	fatals []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Fatalf(format string, args ...any) {
+	r.fatals = append(r.fatals, fmt.Sprintf(format, args...))
+}
+
+func TestRun(t *testing.T) {
+	Run(t, filepath.Join("testdata", "scaffold"), filepath.Join("testdata", "golden"), Options{Functions: testFunctions})
+}
+
+func TestRun_mismatch(t *testing.T) {
+	r := &recordingT{}
+
+	Run(r, filepath.Join("testdata", "scaffold"), filepath.Join("testdata", "golden"), Options{
+		Functions:  testFunctions,
+		ValueFiles: []string{filepath.Join("testdata", "override.yaml")},
+	})
+
+	assert.Empty(t, r.fatals)
+	assert.Equal(t, []string{
+		"README.md: content mismatch\n" +
+			"--- golden/README.md\n" +
+			"+++ generated/README.md\n" +
+			"@@ -1,3 +1,3 @@\n" +
+			" # service\n" +
+			" \n" +
+			"-Owned by platform.\n" +
+			"+Owned by payments.\n",
+		"1 file(s) in testdata/golden don't match the scaffold testdata/scaffold, run the tests with SCAFFOLDTEST_UPDATE=1 to refresh them:\n  README.md",
+	}, r.errors)
+}
+
+func TestRun_renderError(t *testing.T) {
+	r := &recordingT{}
+
+	Run(r, filepath.Join("testdata", "scaffold"), filepath.Join("testdata", "golden"), Options{})
+
+	assert.Empty(t, r.errors)
+	assert.Equal(t, []string{"unable to render scaffold testdata/scaffold: no functions specified in the context"}, r.fatals)
+}
+
+func TestRun_update(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		envVar string
+	}{
+		{
+			name: "Should update the golden files with the option",
+			opts: Options{Functions: testFunctions, Update: true},
+		},
+		{
+			name:   "Should update the golden files with the environment variable",
+			opts:   Options{Functions: testFunctions},
+			envVar: "true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenDir := filepath.Join(t.TempDir(), "golden")
+			assert.NoError(t, os.MkdirAll(goldenDir, os.ModePerm))
+			assert.NoError(t, os.WriteFile(filepath.Join(goldenDir, "README.md"), []byte("outdated"), 0644))
+			assert.NoError(t, os.WriteFile(filepath.Join(goldenDir, "stale.txt"), []byte("stale"), 0644))
+			mockGetenv(t, tt.envVar)
+			r := &recordingT{}
+
+			Run(r, filepath.Join("testdata", "scaffold"), goldenDir, tt.opts)
+
+			assert.Empty(t, r.fatals)
+			assert.Equal(t, []string{
+				"stale.txt: golden file not generated",
+				"1 file(s) in " + goldenDir + " are not generated by the scaffold testdata/scaffold, remove them:\n  stale.txt",
+			}, r.errors)
+			assert.FileExists(t, filepath.Join(goldenDir, "stale.txt"))
+
+			assert.NoError(t, os.Remove(filepath.Join(goldenDir, "stale.txt")))
+			diffs, err := Compare(filepath.Join("testdata", "golden"), readFiles(t, goldenDir))
+			assert.NoError(t, err)
+			assert.Empty(t, diffs)
+		})
+	}
+}
+
+func TestRun_ShouldNotUpdateIfTheEnvironmentVariableIsFalse(t *testing.T) {
+	goldenDir := filepath.Join(t.TempDir(), "golden")
+	mockGetenv(t, "false")
+	r := &recordingT{}
+
+	Run(r, filepath.Join("testdata", "scaffold"), goldenDir, Options{Functions: testFunctions})
+
+	assert.Empty(t, r.fatals)
+	assert.NotEmpty(t, r.errors)
+	assert.NoDirExists(t, goldenDir)
+}
+
+func mockGetenv(t *testing.T, value string) {
+	originalValue := getenv
+	getenv = func(key string) string {
+		assert.Equal(t, UpdateEnv, key)
+		return value
+	}
+	t.Cleanup(func() { getenv = originalValue })
+}
@@ -0,0 +1,31 @@
+package scaffoldtest
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+)
+
+// Update writes the files in goldenDir, replacing the existing ones with the
+// same path. Other files are not removed, as goldenDir may contain files that
+// are not golden ones: Compare reports the stale golden files, that are not
+// generated any more, so that they can be removed manually.
+func Update(goldenDir string, files []collectors.GeneratedFile) error {
+	for _, file := range files {
+		path := filepath.Join(goldenDir, filepath.FromSlash(file.Path))
+		err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(path, file.Content, file.Mode)
+		if err != nil {
+			return err
+		}
+		err = os.Chmod(path, file.Mode)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package scaffoldtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate(t *testing.T) {
+	goldenDir := filepath.Join(t.TempDir(), "golden")
+	assert.NoError(t, os.MkdirAll(filepath.Join(goldenDir, "old"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(goldenDir, "old", "stale.txt"), []byte("stale"), 0644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(goldenDir, "a", "b"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(goldenDir, "a", "b", "script.sh"), []byte("outdated"), 0644))
+
+	err := Update(goldenDir, []collectors.GeneratedFile{
+		{Path: "a/b/script.sh", Mode: 0755, Content: []byte("some-script")},
+		{Path: "README.md", Mode: 0644, Content: []byte("some-content")},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"a/b/script.sh": []byte("some-script"),
+		"old/stale.txt": []byte("stale"),
+		"README.md":     []byte("some-content"),
+	}, mustReadGoldenFiles(t, goldenDir))
+	info, err := os.Stat(filepath.Join(goldenDir, "a", "b", "script.sh"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func mustReadGoldenFiles(t *testing.T, dir string) map[string][]byte {
+	files, err := readGoldenFiles(dir)
+	assert.NoError(t, err)
+	return files
+}
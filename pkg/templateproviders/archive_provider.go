@@ -0,0 +1,163 @@
+package templateproviders
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+type ArchiveProviderOptions struct {
+	StripComponents int            // Number of leading components removed from the paths of the entries, like tar --strip-components
+	Dir             string         // Folder of the archive that contains the templates, after removing the leading components; defaults to the root
+	Filter          filters.Filter // Filter of the templates, applied to their path relative to Dir; optional
+	Sort            bool           // Return the templates of tar.gz archives sorted by path, buffering them in memory; zip templates are always sorted
+}
+
+// templatePath returns the path of the template for the entry of an archive,
+// relative to the Dir folder, and whether the entry is part of that folder and
+// accepted by the filter with the data of the run
+func (o *ArchiveProviderOptions) templatePath(name string, data map[string]interface{}) (string, bool, error) {
+	name = strings.TrimPrefix(filepath.ToSlash(name), "/")
+	cleaned := path.Clean(name)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false, fmt.Errorf("invalid path %q in the archive", name)
+	}
+
+	components := strings.Split(cleaned, "/")
+	if len(components) <= o.StripComponents {
+		return "", false, nil
+	}
+	relativePath := strings.Join(components[o.StripComponents:], "/")
+
+	dir := strings.Trim(path.Clean("/"+filepath.ToSlash(o.Dir)), "/")
+	if len(dir) > 0 {
+		var ok bool
+		relativePath, ok = strings.CutPrefix(relativePath, dir+"/")
+		if !ok {
+			return "", false, nil
+		}
+	}
+
+	relativePath = filepath.FromSlash(relativePath)
+	if o.Filter != nil {
+		accepted, err := filters.AcceptData(o.Filter, relativePath, data)
+		if err != nil || !accepted {
+			return "", false, err
+		}
+	}
+	return relativePath, true, nil
+}
+
+type archiveFileProvider struct {
+	path   string
+	opts   ArchiveProviderOptions
+	data   map[string]interface{}
+	file   *os.File
+	opened bool
+	pipeline.TemplateProvider
+}
+
+// NewArchiveFileProvider creates a provider that reads the templates from the
+// zip (.zip) or tar.gz (.tar.gz or .tgz) archive at path, without extracting
+// it or loading it in memory. The archive is checked when the provider is
+// created, but the file is opened on the first call to NextTemplate, and
+// closed once all the templates have been read, on error, or by Close (the
+// pipeline closes the provider when the run completes). The provider can be
+// reopened, and reads the archive again.
+func NewArchiveFileProvider(archivePath string, opts ArchiveProviderOptions) (pipeline.TemplateProvider, error) {
+	name := strings.ToLower(archivePath)
+	if !strings.HasSuffix(name, ".zip") && !strings.HasSuffix(name, ".tar.gz") && !strings.HasSuffix(name, ".tgz") {
+		return nil, fmt.Errorf("unsupported archive format: %s", archivePath)
+	}
+
+	provider := &archiveFileProvider{
+		path: archivePath,
+		opts: opts,
+	}
+	err := provider.open()
+	if err != nil {
+		return nil, err
+	}
+	provider.Close()
+	provider.file = nil
+	provider.TemplateProvider = nil
+	return provider, nil
+}
+
+// open opens the archive file, and creates the provider of its templates
+func (p *archiveFileProvider) open() error {
+	file, err := os.Open(p.path)
+	if err != nil {
+		return err
+	}
+
+	var provider pipeline.TemplateProvider
+	if strings.HasSuffix(strings.ToLower(p.path), ".zip") {
+		var info os.FileInfo
+		info, err = file.Stat()
+		if err == nil {
+			provider, err = NewZipProvider(file, info.Size(), p.opts)
+		}
+	} else {
+		provider, err = NewTarGzProvider(file, p.opts)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	pipeline.SetTemplateProviderData(provider, p.data)
+	p.file = file
+	p.TemplateProvider = provider
+	return nil
+}
+
+// NextTemplate returns the next template of the archive, opening the file on
+// the first call, and closes the file once there are no more templates, or on
+// error
+func (p *archiveFileProvider) NextTemplate() (*pipeline.Template, error) {
+	if !p.opened {
+		p.opened = true
+		err := p.open()
+		if err != nil {
+			return nil, err
+		}
+	}
+	tpl, err := p.TemplateProvider.NextTemplate()
+	if err != nil {
+		p.Close()
+	}
+	return tpl, err
+}
+
+// Close closes the archive file, if open
+func (p *archiveFileProvider) Close() error {
+	if p.file == nil {
+		return nil
+	}
+	err := p.file.Close()
+	if errors.Is(err, os.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// SetData sets the data on the archive provider, see
+// pipeline.DataAwareTemplateProvider
+func (p *archiveFileProvider) SetData(data map[string]interface{}) {
+	p.data = data
+	if p.TemplateProvider != nil {
+		pipeline.SetTemplateProviderData(p.TemplateProvider, data)
+	}
+}
+
+// Reopen returns a new provider that reads the archive from the beginning
+func (p *archiveFileProvider) Reopen() (pipeline.TemplateProvider, error) {
+	return NewArchiveFileProvider(p.path, p.opts)
+}
@@ -0,0 +1,493 @@
+package templateproviders
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// archiveTestEntry is an entry of an archive created by the tests
+type archiveTestEntry struct {
+	name    string
+	mode    os.FileMode
+	content string
+	link    bool
+}
+
+var archiveTestEntries = []archiveTestEntry{
+	{name: "project-1.0/", mode: os.ModeDir | 0755},
+	{name: "project-1.0/templates/", mode: os.ModeDir | 0755},
+	{name: "project-1.0/templates/README.md", mode: 0644, content: "some-readme"},
+	{name: "project-1.0/templates/bin/run.sh", mode: 0755, content: "some-script"},
+	{name: "project-1.0/templates/link", mode: 0777, content: "README.md", link: true},
+	{name: "project-1.0/other.txt", mode: 0600, content: "other"},
+}
+
+func createZip(t *testing.T, entries []archiveTestEntry) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name}
+		mode := entry.mode
+		if entry.link {
+			mode |= os.ModeSymlink
+		}
+		header.SetMode(mode)
+		w, err := writer.CreateHeader(header)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(entry.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func createTarGz(t *testing.T, entries []archiveTestEntry) []byte {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	writer := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: int64(entry.mode.Perm()), Typeflag: tar.TypeReg, Size: int64(len(entry.content))}
+		switch {
+		case entry.mode.IsDir():
+			header.Typeflag = tar.TypeDir
+		case entry.link:
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = entry.content
+			header.Size = 0
+		}
+		assert.NoError(t, writer.WriteHeader(header))
+		if header.Typeflag == tar.TypeReg {
+			_, err := writer.Write([]byte(entry.content))
+			assert.NoError(t, err)
+		}
+	}
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, gzipWriter.Close())
+	return buffer.Bytes()
+}
+
+// archiveTestTemplate is a template returned by an archive provider
+type archiveTestTemplate struct {
+	path    string
+	mode    os.FileMode
+	content string
+}
+
+func readArchiveTemplates(t *testing.T, p pipeline.TemplateProvider) ([]archiveTestTemplate, error) {
+	templates := make([]archiveTestTemplate, 0)
+	for {
+		tpl, err := p.NextTemplate()
+		if errors.Is(err, io.EOF) {
+			return templates, nil
+		}
+		if err != nil {
+			return templates, err
+		}
+		content, err := io.ReadAll(tpl.Reader)
+		assert.NoError(t, err)
+		assert.NoError(t, tpl.Reader.Close())
+		templates = append(templates, archiveTestTemplate{path: tpl.Path, mode: tpl.Mode, content: string(content)})
+	}
+}
+
+func TestArchiveProviders(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []archiveTestEntry
+		opts    ArchiveProviderOptions
+		want    []archiveTestTemplate
+		wantErr error
+	}{
+		{
+			name:    "Should return all the files",
+			entries: archiveTestEntries,
+			want: []archiveTestTemplate{
+				{path: filepath.Join("project-1.0", "other.txt"), mode: 0600, content: "other"},
+				{path: filepath.Join("project-1.0", "templates", "README.md"), mode: 0644, content: "some-readme"},
+				{path: filepath.Join("project-1.0", "templates", "bin", "run.sh"), mode: 0755, content: "some-script"},
+			},
+		},
+		{
+			name:    "Should strip leading components",
+			entries: archiveTestEntries,
+			opts:    ArchiveProviderOptions{StripComponents: 1},
+			want: []archiveTestTemplate{
+				{path: "other.txt", mode: 0600, content: "other"},
+				{path: filepath.Join("templates", "README.md"), mode: 0644, content: "some-readme"},
+				{path: filepath.Join("templates", "bin", "run.sh"), mode: 0755, content: "some-script"},
+			},
+		},
+		{
+			name:    "Should return the files of the folder",
+			entries: archiveTestEntries,
+			opts:    ArchiveProviderOptions{StripComponents: 1, Dir: "templates/"},
+			want: []archiveTestTemplate{
+				{path: "README.md", mode: 0644, content: "some-readme"},
+				{path: filepath.Join("bin", "run.sh"), mode: 0755, content: "some-script"},
+			},
+		},
+		{
+			name:    "Should apply the filter to the relative paths",
+			entries: archiveTestEntries,
+			opts:    ArchiveProviderOptions{Dir: "project-1.0/templates", Filter: &mockFilter{File: "bin", Include: false}},
+			want: []archiveTestTemplate{
+				{path: "README.md", mode: 0644, content: "some-readme"},
+			},
+		},
+		{
+			name:    "Should ignore files with less components than the stripped ones",
+			entries: archiveTestEntries,
+			opts:    ArchiveProviderOptions{StripComponents: 3},
+			want: []archiveTestTemplate{
+				{path: "run.sh", mode: 0755, content: "some-script"},
+			},
+		},
+		{
+			name:    "Should return error for paths outside the archive",
+			entries: []archiveTestEntry{{name: "project/../../evil.sh", mode: 0755, content: "evil"}},
+			want:    []archiveTestTemplate{},
+			wantErr: errors.New(`invalid path "project/../../evil.sh" in the archive`),
+		},
+	}
+	formats := []struct {
+		name        string
+		newProvider func(t *testing.T, entries []archiveTestEntry, opts ArchiveProviderOptions) pipeline.TemplateProvider
+	}{
+		{
+			name: "zip",
+			newProvider: func(t *testing.T, entries []archiveTestEntry, opts ArchiveProviderOptions) pipeline.TemplateProvider {
+				content := createZip(t, entries)
+				p, err := NewZipProvider(bytes.NewReader(content), int64(len(content)), opts)
+				assert.NoError(t, err)
+				return p
+			},
+		},
+		{
+			name: "sorted tar.gz",
+			newProvider: func(t *testing.T, entries []archiveTestEntry, opts ArchiveProviderOptions) pipeline.TemplateProvider {
+				opts.Sort = true
+				p, err := NewTarGzProvider(bytes.NewReader(createTarGz(t, entries)), opts)
+				assert.NoError(t, err)
+				return p
+			},
+		},
+	}
+	for _, format := range formats {
+		t.Run(format.name, func(t *testing.T) {
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					p := format.newProvider(t, tt.entries, tt.opts)
+
+					got, err := readArchiveTemplates(t, p)
+
+					assertutils.AssertEqualErrors(t, tt.wantErr, err)
+					assert.Equal(t, tt.want, got)
+				})
+			}
+		})
+	}
+}
+
+func TestNewTarGzProvider_archiveOrder(t *testing.T) {
+	p, err := NewTarGzProvider(bytes.NewReader(createTarGz(t, archiveTestEntries)), ArchiveProviderOptions{StripComponents: 1})
+	assert.NoError(t, err)
+	assert.IsType(t, &tarGzProvider{}, p)
+
+	got, err := readArchiveTemplates(t, p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []archiveTestTemplate{
+		{path: filepath.Join("templates", "README.md"), mode: 0644, content: "some-readme"},
+		{path: filepath.Join("templates", "bin", "run.sh"), mode: 0755, content: "some-script"},
+		{path: "other.txt", mode: 0600, content: "other"},
+	}, got)
+	_, err = p.NextTemplate()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestNewArchiveProviders_invalidArchive(t *testing.T) {
+	zipProvider, err := NewZipProvider(bytes.NewReader([]byte("some-content")), 12, ArchiveProviderOptions{})
+	assertutils.AssertEqualErrors(t, errors.New("zip: not a valid zip file"), err)
+	assert.Nil(t, zipProvider)
+
+	tarGzProvider, err := NewTarGzProvider(bytes.NewReader([]byte("some-content")), ArchiveProviderOptions{})
+	assertutils.AssertEqualErrors(t, errors.New("gzip: invalid header"), err)
+	assert.Nil(t, tarGzProvider)
+}
+
+func TestNewArchiveFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "scaffold.zip")
+	assert.NoError(t, os.WriteFile(zipPath, createZip(t, archiveTestEntries), 0644))
+	tgzPath := filepath.Join(dir, "scaffold.TGZ")
+	assert.NoError(t, os.WriteFile(tgzPath, createTarGz(t, archiveTestEntries), 0644))
+	tarGzPath := filepath.Join(dir, "scaffold.tar.gz")
+	assert.NoError(t, os.WriteFile(tarGzPath, createTarGz(t, archiveTestEntries), 0644))
+	want := []archiveTestTemplate{
+		{path: "README.md", mode: 0644, content: "some-readme"},
+		{path: filepath.Join("bin", "run.sh"), mode: 0755, content: "some-script"},
+	}
+
+	for _, archivePath := range []string{zipPath, tgzPath, tarGzPath} {
+		t.Run(filepath.Base(archivePath), func(t *testing.T) {
+			p, err := NewArchiveFileProvider(archivePath, ArchiveProviderOptions{StripComponents: 1, Dir: "templates"})
+			assert.NoError(t, err)
+			got, err := readArchiveTemplates(t, p)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+			_, err = p.(*archiveFileProvider).file.Stat()
+			assert.ErrorIs(t, err, os.ErrClosed)
+
+			reopened, err := pipeline.ReopenTemplateProvider(p)
+			assert.NoError(t, err)
+			got, err = readArchiveTemplates(t, reopened)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestNewArchiveFileProvider_errors(t *testing.T) {
+	dir := t.TempDir()
+	tarPath := filepath.Join(dir, "scaffold.tar")
+	assert.NoError(t, os.WriteFile(tarPath, []byte("some-content"), 0644))
+	invalidZipPath := filepath.Join(dir, "scaffold.zip")
+	assert.NoError(t, os.WriteFile(invalidZipPath, []byte("some-content"), 0644))
+	tests := []struct {
+		name        string
+		archivePath string
+		wantErr     error
+	}{
+		{
+			name:        "Should return error if the archive doesn't exist",
+			archivePath: filepath.Join(dir, "missing.zip"),
+			wantErr:     errors.New("open " + filepath.Join(dir, "missing.zip") + ": no such file or directory"),
+		},
+		{
+			name:        "Should return error if the format is not supported",
+			archivePath: tarPath,
+			wantErr:     errors.New("unsupported archive format: " + tarPath),
+		},
+		{
+			name:        "Should return error if the archive is not valid",
+			archivePath: invalidZipPath,
+			wantErr:     errors.New("zip: not a valid zip file"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewArchiveFileProvider(tt.archivePath, ArchiveProviderOptions{})
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func Test_archiveFileProvider_Close(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "scaffold.zip")
+	assert.NoError(t, os.WriteFile(archivePath, createZip(t, archiveTestEntries), 0644))
+
+	p, err := NewArchiveFileProvider(archivePath, ArchiveProviderOptions{StripComponents: 1, Dir: "templates"})
+	assert.NoError(t, err)
+	provider := p.(*archiveFileProvider)
+	assert.Nil(t, provider.file)
+	assert.NoError(t, pipeline.CloseTemplateProvider(p))
+
+	tpl, err := p.NextTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, "README.md", tpl.Path)
+	_, err = provider.file.Stat()
+	assert.NoError(t, err)
+
+	assert.NoError(t, pipeline.CloseTemplateProvider(p))
+	_, err = provider.file.Stat()
+	assert.ErrorIs(t, err, os.ErrClosed)
+	assert.NoError(t, pipeline.CloseTemplateProvider(p))
+}
+
+func Test_archiveFileProvider_failedRuns(t *testing.T) {
+	if _, err := os.Stat("/proc/self/fd"); err != nil {
+		t.Skip("open file descriptors can't be counted on this platform")
+	}
+	countFds := func() int {
+		entries, err := os.ReadDir("/proc/self/fd")
+		assert.NoError(t, err)
+		return len(entries)
+	}
+	entries := []archiveTestEntry{
+		{name: "a.txt", mode: 0644, content: "{{ fail }}"},
+		{name: "b.txt", mode: 0644, content: "some-content"},
+	}
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "scaffold.zip")
+	assert.NoError(t, os.WriteFile(zipPath, createZip(t, entries), 0644))
+	tarGzPath := filepath.Join(dir, "scaffold.tar.gz")
+	assert.NoError(t, os.WriteFile(tarGzPath, createTarGz(t, entries), 0644))
+
+	for _, archivePath := range []string{zipPath, tarGzPath} {
+		t.Run(filepath.Base(archivePath), func(t *testing.T) {
+			want := countFds()
+
+			p, err := NewArchiveFileProvider(archivePath, ArchiveProviderOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, want, countFds())
+			pipe, err := pipeline.NewPipelineBuilder().
+				WithTemplateProvider(p).
+				WithNamedTemplatesProvider(p).
+				WithCollector(collectors.NewMemoryCollector(nil)).
+				WithFunctions(map[string]interface{}{"fail": func() (string, error) { return "", errors.New("some-error") }}).
+				Build()
+			assert.NoError(t, err)
+			for i := 0; i < 5; i++ {
+				assert.Error(t, pipe.Process(map[string]interface{}{}))
+			}
+
+			assert.Equal(t, want, countFds())
+		})
+	}
+}
+
+func Test_zipProvider_Reopen(t *testing.T) {
+	content := createZip(t, archiveTestEntries)
+	p, err := NewZipProvider(bytes.NewReader(content), int64(len(content)), ArchiveProviderOptions{})
+	assert.NoError(t, err)
+	first, err := p.NextTemplate()
+	assert.NoError(t, err)
+
+	got, err := p.(*zipProvider).Reopen()
+
+	assert.NoError(t, err)
+	reopened, err := got.NextTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, first.Path, reopened.Path)
+	next, err := p.NextTemplate()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Path, next.Path)
+}
+
+func TestArchiveProviders_dataFilter(t *testing.T) {
+	filter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "bin/", Condition: "{{ .scripts }}"})
+	assert.NoError(t, err)
+	opts := ArchiveProviderOptions{StripComponents: 1, Dir: "templates", Filter: filter}
+	zipContent := createZip(t, archiveTestEntries)
+	tarGzContent := createTarGz(t, archiveTestEntries)
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "scaffold.zip")
+	assert.NoError(t, os.WriteFile(zipPath, zipContent, 0644))
+	tarGzPath := filepath.Join(dir, "scaffold.tar.gz")
+	assert.NoError(t, os.WriteFile(tarGzPath, tarGzContent, 0644))
+	server, _ := newArchiveServer(t, map[string][]byte{"/scaffold.zip": zipContent})
+
+	providers := []struct {
+		name        string
+		newProvider func(opts ArchiveProviderOptions) (pipeline.TemplateProvider, error)
+	}{
+		{
+			name: "zip",
+			newProvider: func(opts ArchiveProviderOptions) (pipeline.TemplateProvider, error) {
+				return NewZipProvider(bytes.NewReader(zipContent), int64(len(zipContent)), opts)
+			},
+		},
+		{
+			name: "tar.gz",
+			newProvider: func(opts ArchiveProviderOptions) (pipeline.TemplateProvider, error) {
+				return NewTarGzProvider(bytes.NewReader(tarGzContent), opts)
+			},
+		},
+		{
+			name: "sorted tar.gz",
+			newProvider: func(opts ArchiveProviderOptions) (pipeline.TemplateProvider, error) {
+				opts.Sort = true
+				return NewTarGzProvider(bytes.NewReader(tarGzContent), opts)
+			},
+		},
+		{
+			name: "zip file",
+			newProvider: func(opts ArchiveProviderOptions) (pipeline.TemplateProvider, error) {
+				return NewArchiveFileProvider(zipPath, opts)
+			},
+		},
+		{
+			name: "tar.gz file",
+			newProvider: func(opts ArchiveProviderOptions) (pipeline.TemplateProvider, error) {
+				return NewArchiveFileProvider(tarGzPath, opts)
+			},
+		},
+		{
+			name: "http",
+			newProvider: func(opts ArchiveProviderOptions) (pipeline.TemplateProvider, error) {
+				return NewHTTPProvider(context.Background(), HTTPProviderOptions{
+					URL:      server.URL + "/scaffold.zip",
+					SHA256:   sha256Hex(zipContent),
+					CacheDir: filepath.Join(dir, "cache"),
+					Archive:  opts,
+				})
+			},
+		},
+	}
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		want []string
+	}{
+		{
+			name: "Should return the templates accepted with the data",
+			data: map[string]interface{}{"scripts": true},
+			want: []string{"README.md", filepath.Join("bin", "run.sh")},
+		},
+		{
+			name: "Should not return the templates rejected with the data",
+			data: map[string]interface{}{"scripts": false},
+			want: []string{"README.md"},
+		},
+	}
+	for _, provider := range providers {
+		t.Run(provider.name, func(t *testing.T) {
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					p, err := provider.newProvider(opts)
+					assert.NoError(t, err)
+					pipeline.SetTemplateProviderData(p, tt.data)
+
+					got, err := readArchiveTemplates(t, p)
+
+					assert.NoError(t, err)
+					paths := make([]string, 0, len(got))
+					for _, tpl := range got {
+						paths = append(paths, tpl.path)
+					}
+					assert.Equal(t, tt.want, paths)
+				})
+			}
+
+			t.Run("Should return error if the filter fails", func(t *testing.T) {
+				failingFilter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "*", Condition: "{{ index .list 5 }}"})
+				assert.NoError(t, err)
+				failingOpts := opts
+				failingOpts.Filter = failingFilter
+				p, err := provider.newProvider(failingOpts)
+				assert.NoError(t, err)
+				pipeline.SetTemplateProviderData(p, map[string]interface{}{"list": []string{}})
+
+				_, err = readArchiveTemplates(t, p)
+
+				assert.ErrorContains(t, err, "unable to evaluate the condition")
+			})
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package templateproviders
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
 	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
@@ -11,41 +15,151 @@ import (
 
 var open = os.Open
 
+type FileSystemProviderOptions struct {
+	InputDir string         // Folder with the templates
+	Filter   filters.Filter // Filter of the templates, applied to their path relative to InputDir; optional
+	Debug    io.Writer      // Writer where the accepted and rejected paths are listed, with the reasons (see filters.Explain); optional
+}
+
 type fileSystemProvider struct {
 	filter  filters.Filter
+	debug   io.Writer
 	indexer *filesindex.Indexer
+	data    map[string]interface{}
+	paths   []string
+	listed  bool
+	next    int
 }
 
 // NewFileSystemProvider creates a new instance of a FileProvider that reads
-// file from the filesystem.
+// file from the filesystem. The files are returned sorted by path, in
+// lexicographic order of their slash separated form (i.e. "a.txt" comes before
+// "a/b.txt"), regardless of the platform. If the filter is a
+// filters.DataFilter, it receives the data of the run.
 func NewFileSystemProvider(inputDir string, filter filters.Filter) pipeline.TemplateProvider {
+	return NewFileSystemProviderWithOpts(FileSystemProviderOptions{
+		InputDir: inputDir,
+		Filter:   filter,
+	})
+}
+
+// NewFileSystemProviderWithOpts creates a file system provider with the
+// provided options, see NewFileSystemProvider
+func NewFileSystemProviderWithOpts(opts FileSystemProviderOptions) pipeline.TemplateProvider {
 	return &fileSystemProvider{
-		filter: filter,
+		filter: opts.Filter,
+		debug:  opts.Debug,
 		indexer: &filesindex.Indexer{
-			Dir: inputDir,
+			Dir: opts.InputDir,
 		},
 	}
 }
 
 func (p *fileSystemProvider) NextTemplate() (*pipeline.Template, error) {
+	if !p.listed {
+		err := p.list()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.next >= len(p.paths) {
+		return nil, io.EOF
+	}
+	relativePath := p.paths[p.next]
+	p.next++
+
+	reader, err := open(filepath.Join(p.indexer.Dir, relativePath))
+	if err != nil {
+		return nil, err
+	}
+	info, err := reader.Stat()
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	return &pipeline.Template{
+		Reader: reader,
+		Path:   relativePath,
+		Mode:   info.Mode().Perm(),
+	}, nil
+}
+
+// list indexes the accepted files of the folder, sorted by path
+func (p *fileSystemProvider) list() error {
+	debugLines := make(map[string]string)
 	for {
 		item, err := p.indexer.NextFile()
+		if errors.Is(err, io.EOF) {
+			break
+		}
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		absolutePath := filepath.Join(p.indexer.Dir, item.Path())
-		relativePath := item.Path()
-		if p.filter == nil || p.filter.Accept(relativePath) {
-			reader, err := open(absolutePath)
-			if err != nil {
-				return nil, err
-			}
-
-			return &pipeline.Template{
-				Reader: reader,
-				Path:   relativePath,
-			}, nil
+		accepted, reason, err := p.accept(item.Path())
+		if err != nil {
+			return err
+		}
+		if accepted {
+			p.paths = append(p.paths, item.Path())
 		}
+		if p.debug != nil {
+			debugLines[item.Path()] = reason
+		}
+	}
+
+	p.listed = true
+	sort.Slice(p.paths, func(i, j int) bool { return comparePaths(p.paths[i], p.paths[j]) < 0 })
+	return p.printDebug(debugLines)
+}
+
+// accept returns whether the filter accepts the path, with the reason if the
+// debug listing is enabled
+func (p *fileSystemProvider) accept(path string) (bool, string, error) {
+	if p.filter == nil {
+		return true, "accepted, no filter", nil
 	}
+	if p.debug == nil {
+		accepted, err := filters.AcceptData(p.filter, path, p.data)
+		return accepted, "", err
+	}
+
+	explanation, err := filters.Explain(p.filter, path, p.data)
+	if err != nil {
+		return false, "", err
+	}
+	return explanation.Accepted, explanation.String(), nil
+}
+
+// printDebug prints the lines of the debug listing, sorted by path
+func (p *fileSystemProvider) printDebug(lines map[string]string) error {
+	paths := make([]string, 0, len(lines))
+	for path := range lines {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool { return comparePaths(paths[i], paths[j]) < 0 })
+
+	for _, path := range paths {
+		_, err := fmt.Fprintf(p.debug, "%s: %s\n", filepath.ToSlash(path), lines[path])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetData sets the data passed to the filter, see pipeline.DataAwareTemplateProvider
+func (p *fileSystemProvider) SetData(data map[string]interface{}) {
+	p.data = data
+}
+
+// Reopen returns a new provider that reads the same folder from the beginning
+func (p *fileSystemProvider) Reopen() (pipeline.TemplateProvider, error) {
+	return NewFileSystemProviderWithOpts(FileSystemProviderOptions{
+		InputDir: p.indexer.Dir,
+		Filter:   p.filter,
+		Debug:    p.debug,
+	}), nil
 }
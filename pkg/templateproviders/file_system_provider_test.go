@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/pasdam/go-utils/pkg/assertutils"
@@ -194,3 +195,172 @@ func mockOpen(t *testing.T, expectedName string, file *os.File, err error) {
 	}
 	t.Cleanup(func() { open = originalValue })
 }
+
+func Test_fileSystemProvider_Reopen(t *testing.T) {
+	filter := filters.NewNoOpFilter()
+	p := NewFileSystemProvider(filepath.Join("testdata", "file_system_provider"), filter)
+	first, err := p.NextTemplate()
+	assert.NoError(t, err)
+	defer first.Reader.Close()
+
+	got, err := p.(*fileSystemProvider).Reopen()
+
+	assert.NoError(t, err)
+	assert.Equal(t, filter, got.(*fileSystemProvider).filter)
+	reopened, err := got.NextTemplate()
+	assert.NoError(t, err)
+	defer reopened.Reader.Close()
+	assert.Equal(t, first.Path, reopened.Path)
+	next, err := p.NextTemplate()
+	assert.NoError(t, err)
+	defer next.Reader.Close()
+	assert.NotEqual(t, first.Path, next.Path)
+}
+
+func Test_fileSystemProvider_NextTemplate_Mode(t *testing.T) {
+	dir := filetestutils.TempDir(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "script.sh"), []byte("some-script"), 0755))
+	assert.NoError(t, os.Chmod(filepath.Join(dir, "script.sh"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("some-secret"), 0600))
+	assert.NoError(t, os.Chmod(filepath.Join(dir, "secret.txt"), 0600))
+	p := NewFileSystemProvider(dir, nil)
+
+	script, err := p.NextTemplate()
+	assert.NoError(t, err)
+	script.Reader.Close()
+	secret, err := p.NextTemplate()
+	assert.NoError(t, err)
+	secret.Reader.Close()
+
+	assert.Equal(t, "script.sh", script.Path)
+	assert.Equal(t, os.FileMode(0755), script.Mode)
+	assert.Equal(t, "secret.txt", secret.Path)
+	assert.Equal(t, os.FileMode(0600), secret.Mode)
+}
+
+func Test_fileSystemProvider_NextTemplate_order(t *testing.T) {
+	dir := filetestutils.TempDir(t)
+	for _, path := range []string{"b/c.txt", "a.txt", "a/x.txt", "a-b.txt", "B.txt"} {
+		fullPath := filepath.Join(dir, filepath.FromSlash(path))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(fullPath), os.ModePerm))
+		assert.NoError(t, os.WriteFile(fullPath, []byte(path), 0644))
+	}
+	p := NewFileSystemProvider(dir, nil)
+
+	paths := make([]string, 0)
+	for {
+		got, err := p.NextTemplate()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		got.Reader.Close()
+		paths = append(paths, filepath.ToSlash(got.Path))
+	}
+
+	assert.Equal(t, []string{"B.txt", "a-b.txt", "a.txt", "a/x.txt", "b/c.txt"}, paths)
+}
+
+func Test_fileSystemProvider_NextTemplate_data(t *testing.T) {
+	dir := filetestutils.TempDir(t)
+	for _, path := range []string{"grpc/server.go", "main.go"} {
+		fullPath := filepath.Join(dir, filepath.FromSlash(path))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(fullPath), os.ModePerm))
+		assert.NoError(t, os.WriteFile(fullPath, []byte(path), 0644))
+	}
+	filter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "grpc/", Condition: "{{ .grpc }}"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		data      map[string]interface{}
+		wantPaths []string
+	}{
+		{
+			name:      "Should return the templates accepted with the data",
+			data:      map[string]interface{}{"grpc": true},
+			wantPaths: []string{"grpc/server.go", "main.go"},
+		},
+		{
+			name:      "Should not return the templates rejected with the data",
+			data:      map[string]interface{}{"grpc": false},
+			wantPaths: []string{"main.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewSortedProvider(NewFileSystemProvider(dir, filter))
+			pipeline.SetTemplateProviderData(p, tt.data)
+
+			paths := make([]string, 0)
+			for {
+				got, err := p.NextTemplate()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				assert.NoError(t, err)
+				got.Reader.Close()
+				paths = append(paths, filepath.ToSlash(got.Path))
+			}
+
+			assert.Equal(t, tt.wantPaths, paths)
+		})
+	}
+}
+
+func Test_fileSystemProvider_NextTemplate_ShouldReturnErrorIfTheFilterFails(t *testing.T) {
+	filter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "*", Condition: "{{ index .list 5 }}"})
+	assert.NoError(t, err)
+	p := NewFileSystemProvider(filepath.Join("testdata", "file_system_provider"), filter)
+	pipeline.SetTemplateProviderData(p, map[string]interface{}{"list": []string{}})
+
+	got, err := p.NextTemplate()
+
+	assert.ErrorContains(t, err, "unable to evaluate the condition")
+	assert.Nil(t, got)
+}
+
+func Test_fileSystemProvider_NextTemplate_debug(t *testing.T) {
+	dir := filetestutils.TempDir(t)
+	for _, path := range []string{"main.go", "debug.log", "build/out.txt"} {
+		fullPath := filepath.Join(dir, filepath.FromSlash(path))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(fullPath), os.ModePerm))
+		assert.NoError(t, os.WriteFile(fullPath, []byte(path), 0644))
+	}
+	filter, err := filters.NewGlobFilter(false, "*.log", "build/")
+	assert.NoError(t, err)
+	tests := []struct {
+		name   string
+		filter filters.Filter
+		want   string
+	}{
+		{
+			name:   "Should list the paths with the reasons of the filter",
+			filter: filter,
+			want: "build/out.txt: rejected by glob filter (exclusive), pattern 1 \"build/\" matched\n" +
+				"debug.log: rejected by glob filter (exclusive), pattern 0 \"*.log\" matched\n" +
+				"main.go: accepted by glob filter (exclusive), no pattern matched\n",
+		},
+		{
+			name: "Should list the paths without filter",
+			want: "build/out.txt: accepted, no filter\n" +
+				"debug.log: accepted, no filter\n" +
+				"main.go: accepted, no filter\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			debug := &strings.Builder{}
+			p := NewFileSystemProviderWithOpts(FileSystemProviderOptions{InputDir: dir, Filter: tt.filter, Debug: debug})
+
+			got, err := p.NextTemplate()
+			assert.NoError(t, err)
+			got.Reader.Close()
+
+			assert.Equal(t, tt.want, debug.String())
+			reopened, err := p.(*fileSystemProvider).Reopen()
+			assert.NoError(t, err)
+			assert.Equal(t, debug, reopened.(*fileSystemProvider).debug)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package templateproviders
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"strconv"
+)
+
+// gitObjectID is the SHA-1 id of a git object
+type gitObjectID [20]byte
+
+func (id gitObjectID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// parseGitObjectID parses the hexadecimal representation of an object id
+func parseGitObjectID(s string) (gitObjectID, error) {
+	var id gitObjectID
+	if len(s) != 2*len(id) {
+		return id, fmt.Errorf("invalid object id %q", s)
+	}
+	_, err := hex.Decode(id[:], []byte(s))
+	if err != nil {
+		return id, fmt.Errorf("invalid object id %q", s)
+	}
+	return id, nil
+}
+
+// gitObjectType is the type of a git object, with the values used in packs
+type gitObjectType int
+
+const (
+	gitCommit gitObjectType = 1
+	gitTree   gitObjectType = 2
+	gitBlob   gitObjectType = 3
+	gitTag    gitObjectType = 4
+)
+
+func (t gitObjectType) String() string {
+	switch t {
+	case gitCommit:
+		return "commit"
+	case gitTree:
+		return "tree"
+	case gitBlob:
+		return "blob"
+	case gitTag:
+		return "tag"
+	}
+	return "unknown"
+}
+
+func parseGitObjectType(s string) (gitObjectType, error) {
+	for _, t := range []gitObjectType{gitCommit, gitTree, gitBlob, gitTag} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown object type %q", s)
+}
+
+// gitTreeEntry is an entry of a tree object
+type gitTreeEntry struct {
+	name string
+	mode uint32
+	id   gitObjectID
+}
+
+const (
+	gitModeTree       = 0o040000
+	gitModeExecutable = 0o100755
+	gitModeSymlink    = 0o120000
+	gitModeSubmodule  = 0o160000
+)
+
+func (e *gitTreeEntry) isTree() bool {
+	return e.mode == gitModeTree
+}
+
+// isFile returns whether the entry is a regular file; symbolic links and
+// submodules are not
+func (e *gitTreeEntry) isFile() bool {
+	return e.mode&0o170000 == 0o100000
+}
+
+// fileMode returns the permission bits of a regular file
+func (e *gitTreeEntry) fileMode() fs.FileMode {
+	if e.mode == gitModeExecutable {
+		return 0755
+	}
+	return 0644
+}
+
+// parseGitTree parses the entries of a tree object
+func parseGitTree(content []byte) ([]gitTreeEntry, error) {
+	entries := make([]gitTreeEntry, 0)
+	for len(content) > 0 {
+		space := bytes.IndexByte(content, ' ')
+		if space < 0 {
+			return nil, fmt.Errorf("invalid tree entry: missing mode")
+		}
+		mode, err := strconv.ParseUint(string(content[:space]), 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tree entry mode %q", content[:space])
+		}
+		content = content[space+1:]
+
+		nul := bytes.IndexByte(content, 0)
+		if nul < 0 || len(content) < nul+1+len(gitObjectID{}) {
+			return nil, fmt.Errorf("invalid tree entry: truncated entry")
+		}
+		entry := gitTreeEntry{
+			name: string(content[:nul]),
+			mode: uint32(mode),
+		}
+		copy(entry.id[:], content[nul+1:])
+		entries = append(entries, entry)
+		content = content[nul+1+len(entry.id):]
+	}
+	return entries, nil
+}
+
+// gitHeader returns the value of the specified header of a commit or a tag
+func gitHeader(content []byte, name string) (string, bool) {
+	for len(content) > 0 {
+		line := content
+		newLine := bytes.IndexByte(content, '\n')
+		if newLine >= 0 {
+			line, content = content[:newLine], content[newLine+1:]
+		} else {
+			content = nil
+		}
+		if len(line) == 0 {
+			// end of the headers
+			return "", false
+		}
+		if value, ok := bytes.CutPrefix(line, []byte(name+" ")); ok {
+			return string(value), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,311 @@
+package templateproviders
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	gitOfsDelta gitObjectType = 6
+	gitRefDelta gitObjectType = 7
+
+	// gitMaxDeltaDepth limits the chains of deltas, to detect corrupted packs
+	gitMaxDeltaDepth = 1000
+
+	// gitMaxObjectSize limits the size of the objects, that is read from the
+	// repository, to detect corrupted objects before allocating their content
+	gitMaxObjectSize = 1 << 30
+)
+
+// gitPack is a pack file, with its version 2 index
+type gitPack struct {
+	path    string
+	ids     []gitObjectID
+	offsets []int64
+}
+
+// openGitPack reads the index of the pack with the specified path (without
+// extension)
+func openGitPack(path string) (*gitPack, error) {
+	index, err := os.ReadFile(path + ".idx")
+	if err != nil {
+		return nil, err
+	}
+
+	const headerSize = 8 + 256*4
+	if len(index) < headerSize || !bytes.Equal(index[:8], []byte{0xff, 't', 'O', 'c', 0, 0, 0, 2}) {
+		return nil, fmt.Errorf("unsupported pack index %s", path+".idx")
+	}
+	count := int(binary.BigEndian.Uint32(index[headerSize-4 : headerSize]))
+	idsStart := headerSize
+	offsetsStart := idsStart + count*20 + count*4
+	largeOffsetsStart := offsetsStart + count*4
+	if len(index) < largeOffsetsStart {
+		return nil, fmt.Errorf("truncated pack index %s", path+".idx")
+	}
+
+	pack := &gitPack{
+		path:    path + ".pack",
+		ids:     make([]gitObjectID, count),
+		offsets: make([]int64, count),
+	}
+	for i := 0; i < count; i++ {
+		copy(pack.ids[i][:], index[idsStart+i*20:])
+		offset := binary.BigEndian.Uint32(index[offsetsStart+i*4:])
+		if offset&0x80000000 == 0 {
+			pack.offsets[i] = int64(offset)
+			continue
+		}
+		large := largeOffsetsStart + int(offset&0x7fffffff)*8
+		if len(index) < large+8 {
+			return nil, fmt.Errorf("truncated pack index %s", path+".idx")
+		}
+		pack.offsets[i] = int64(binary.BigEndian.Uint64(index[large:]))
+	}
+	return pack, nil
+}
+
+// offset returns the offset of the object in the pack, if present
+func (p *gitPack) offset(id gitObjectID) (int64, bool) {
+	i := sort.Search(len(p.ids), func(i int) bool { return bytes.Compare(p.ids[i][:], id[:]) >= 0 })
+	if i < len(p.ids) && p.ids[i] == id {
+		return p.offsets[i], true
+	}
+	return 0, false
+}
+
+// withPrefix returns the ids of the objects in the pack that start with the
+// specified hexadecimal prefix
+func (p *gitPack) withPrefix(prefix string) []gitObjectID {
+	ids := make([]gitObjectID, 0)
+	for _, id := range p.ids {
+		if strings.HasPrefix(id.String(), prefix) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// readObject reads the object at the specified offset, resolving the deltas;
+// the base objects of ref deltas are read with readBase
+func (p *gitPack) readObject(offset int64, readBase func(gitObjectID) (gitObjectType, []byte, error)) (gitObjectType, []byte, error) {
+	file, err := os.Open(p.path)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer file.Close()
+
+	return p.readObjectAt(file, offset, readBase, 0)
+}
+
+func (p *gitPack) readObjectAt(file *os.File, offset int64, readBase func(gitObjectID) (gitObjectType, []byte, error), depth int) (gitObjectType, []byte, error) {
+	if depth > gitMaxDeltaDepth {
+		return 0, nil, fmt.Errorf("delta chain too long in %s", p.path)
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(file, offset, 1<<62))
+	objType, size, err := readGitPackObjectHeader(reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read object at offset %d of %s: %w", offset, p.path, err)
+	}
+
+	var baseType gitObjectType
+	var base []byte
+	switch objType {
+	case gitCommit, gitTree, gitBlob, gitTag:
+		content, err := inflate(reader, size)
+		if err != nil {
+			return 0, nil, err
+		}
+		return objType, content, nil
+
+	case gitOfsDelta:
+		distance, err := readGitOffsetDistance(reader)
+		if err != nil {
+			return 0, nil, err
+		}
+		if distance <= 0 || distance > offset {
+			return 0, nil, fmt.Errorf("invalid delta base at offset %d of %s", offset, p.path)
+		}
+		baseType, base, err = p.readObjectAt(file, offset-distance, readBase, depth+1)
+		if err != nil {
+			return 0, nil, err
+		}
+
+	case gitRefDelta:
+		var baseID gitObjectID
+		_, err := io.ReadFull(reader, baseID[:])
+		if err != nil {
+			return 0, nil, err
+		}
+		baseType, base, err = readBase(baseID)
+		if err != nil {
+			return 0, nil, err
+		}
+
+	default:
+		return 0, nil, fmt.Errorf("unsupported object type %d at offset %d of %s", objType, offset, p.path)
+	}
+
+	delta, err := inflate(reader, size)
+	if err != nil {
+		return 0, nil, err
+	}
+	content, err := applyGitDelta(base, delta)
+	if err != nil {
+		return 0, nil, err
+	}
+	return baseType, content, nil
+}
+
+// readGitPackObjectHeader reads the type and the (inflated) size of a pack
+// object; the size is validated by checkGitObjectSize when the object is read
+func readGitPackObjectHeader(reader io.ByteReader) (gitObjectType, int64, error) {
+	b, err := reader.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	objType := gitObjectType((b >> 4) & 0x7)
+	size := int64(b & 0x0f)
+	for shift := 4; b&0x80 != 0; shift += 7 {
+		if shift > 63-7 {
+			return 0, 0, errors.New("object size overflow")
+		}
+		b, err = reader.ReadByte()
+		if err != nil {
+			return 0, 0, err
+		}
+		size |= int64(b&0x7f) << shift
+	}
+	return objType, size, nil
+}
+
+// readGitOffsetDistance reads the distance of the base object of an offset
+// delta
+func readGitOffsetDistance(reader io.ByteReader) (int64, error) {
+	b, err := reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	distance := int64(b & 0x7f)
+	for b&0x80 != 0 {
+		b, err = reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		distance = ((distance + 1) << 7) | int64(b&0x7f)
+	}
+	return distance, nil
+}
+
+// inflate decompresses size bytes of zlib data
+func inflate(reader io.Reader, size int64) ([]byte, error) {
+	err := checkGitObjectSize(size)
+	if err != nil {
+		return nil, err
+	}
+	zlibReader, err := zlib.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer zlibReader.Close()
+
+	return readGitObjectContent(zlibReader, size)
+}
+
+// checkGitObjectSize returns an error if the size of an object, read from the
+// repository, is negative or exceeds gitMaxObjectSize
+func checkGitObjectSize(size int64) error {
+	if size < 0 || size > gitMaxObjectSize {
+		return fmt.Errorf("invalid object size %d", size)
+	}
+	return nil
+}
+
+// readGitObjectContent reads size bytes of content; the buffer grows with the
+// data actually read, so that a corrupted size doesn't allocate memory for
+// content that isn't there
+func readGitObjectContent(reader io.Reader, size int64) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, size))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) != size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return content, nil
+}
+
+// applyGitDelta creates an object applying the delta instructions to base
+func applyGitDelta(base []byte, delta []byte) ([]byte, error) {
+	errInvalidDelta := errors.New("invalid delta")
+
+	reader := bytes.NewReader(delta)
+	baseSize, err := binary.ReadUvarint(reader)
+	if err != nil || baseSize != uint64(len(base)) {
+		return nil, errInvalidDelta
+	}
+	size, err := binary.ReadUvarint(reader)
+	if err != nil || size > gitMaxObjectSize {
+		return nil, errInvalidDelta
+	}
+
+	content := make([]byte, 0, min(size, uint64(len(base)+len(delta))))
+	for reader.Len() > 0 {
+		op, _ := reader.ReadByte()
+		if op&0x80 == 0 {
+			// insert the next op bytes
+			if op == 0 || int(op) > reader.Len() {
+				return nil, errInvalidDelta
+			}
+			insert := make([]byte, op)
+			reader.Read(insert)
+			content = append(content, insert...)
+			continue
+		}
+
+		// copy from base
+		var offset, length uint64
+		for i := 0; i < 4; i++ {
+			if op&(1<<i) != 0 {
+				b, err := reader.ReadByte()
+				if err != nil {
+					return nil, errInvalidDelta
+				}
+				offset |= uint64(b) << (8 * i)
+			}
+		}
+		for i := 0; i < 3; i++ {
+			if op&(1<<(4+i)) != 0 {
+				b, err := reader.ReadByte()
+				if err != nil {
+					return nil, errInvalidDelta
+				}
+				length |= uint64(b) << (8 * i)
+			}
+		}
+		if length == 0 {
+			length = 0x10000
+		}
+		if offset+length > uint64(len(base)) {
+			return nil, errInvalidDelta
+		}
+		content = append(content, base[offset:offset+length]...)
+		if uint64(len(content)) > size {
+			return nil, errInvalidDelta
+		}
+	}
+
+	if uint64(len(content)) != size {
+		return nil, errInvalidDelta
+	}
+	return content, nil
+}
@@ -0,0 +1,105 @@
+package templateproviders
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_applyGitDelta(t *testing.T) {
+	base := []byte("some-base-content")
+	tests := []struct {
+		name    string
+		delta   []byte
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "Should copy from base and insert new data",
+			// base size 17, result size 13, copy 5 bytes at offset 0, insert "new-", copy 4 at 10
+			delta: []byte{17, 13, 0x90, 5, 4, 'n', 'e', 'w', '-', 0x91, 10, 4},
+			want:  []byte("some-new-cont"),
+		},
+		{
+			name:    "Should return error if base size doesn't match",
+			delta:   []byte{16, 5, 0x90, 5},
+			wantErr: errors.New("invalid delta"),
+		},
+		{
+			name:    "Should return error if copy is out of base",
+			delta:   []byte{17, 5, 0x91, 15, 5},
+			wantErr: errors.New("invalid delta"),
+		},
+		{
+			name:    "Should return error if insert is truncated",
+			delta:   []byte{17, 5, 5, 'a'},
+			wantErr: errors.New("invalid delta"),
+		},
+		{
+			name:    "Should return error if result size doesn't match",
+			delta:   []byte{17, 6, 0x90, 5},
+			wantErr: errors.New("invalid delta"),
+		},
+		{
+			name:    "Should return error if result size is too big",
+			delta:   []byte{17, 0x80, 0x80, 0x80, 0x80, 0x10, 0x90, 5},
+			wantErr: errors.New("invalid delta"),
+		},
+		{
+			name:    "Should return error if result exceeds its size",
+			delta:   []byte{17, 4, 0x90, 5},
+			wantErr: errors.New("invalid delta"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyGitDelta(base, tt.delta)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_gitPack_readObject_corrupted(t *testing.T) {
+	tests := []struct {
+		name    string
+		pack    []byte
+		wantErr string
+	}{
+		{
+			name: "Should return error if the size is too big",
+			// blob of 2^39 bytes
+			pack:    []byte{0xb0, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01},
+			wantErr: "invalid object size 549755813888",
+		},
+		{
+			name: "Should return error if the size overflows",
+			// blob with a size encoded in more than 60 bits
+			pack:    []byte{0xb0, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01},
+			wantErr: "object size overflow",
+		},
+		{
+			name:    "Should return error if the content is shorter than the size",
+			pack:    append([]byte{0x3f}, zlibCompress(t, []byte("some-content"))...),
+			wantErr: "unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pack-corrupted.pack")
+			assert.NoError(t, os.WriteFile(path, tt.pack, 0644))
+			pack := &gitPack{path: path}
+
+			objType, content, err := pack.readObject(0, nil)
+
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Equal(t, gitObjectType(0), objType)
+			assert.Nil(t, content)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package templateproviders
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+type GitProviderOptions struct {
+	RepoPath string         // Path of the repository, bare or with a working tree
+	Ref      string         // Branch, tag or commit (also abbreviated) to read the templates from; defaults to HEAD
+	Dir      string         // Folder of the repository that contains the templates; defaults to the root
+	Filter   filters.Filter // Filter of the templates, applied to their path relative to Dir; optional
+}
+
+// gitFile is a file of the tree read by a gitProvider
+type gitFile struct {
+	path string
+	mode fs.FileMode
+	id   gitObjectID
+}
+
+type gitProvider struct {
+	repo   *gitRepository
+	filter filters.Filter
+	data   map[string]interface{}
+	files  []gitFile
+	next   int
+}
+
+// NewGitProvider creates a provider that reads the templates from the tree of
+// a commit of a local git repository, without checking it out. The reference
+// is resolved when the provider is created, and the files are returned sorted
+// by path; symbolic links and submodules are ignored. If the filter is a
+// filters.DataFilter, it receives the data of the run.
+func NewGitProvider(opts GitProviderOptions) (pipeline.TemplateProvider, error) {
+	repo, err := openGitRepository(opts.RepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := opts.Ref
+	if len(ref) == 0 {
+		ref = "HEAD"
+	}
+	tree, err := repo.resolveTree(ref)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve %q in %s: %w", ref, opts.RepoPath, err)
+	}
+	tree, err = subTree(repo, tree, opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]gitFile, 0)
+	err = listGitFiles(repo, tree, "", &files)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+
+	return &gitProvider{
+		repo:   repo,
+		filter: opts.Filter,
+		files:  files,
+	}, nil
+}
+
+// subTree returns the tree of the folder dir
+func subTree(repo *gitRepository, tree gitObjectID, dir string) (gitObjectID, error) {
+	dir = strings.Trim(path.Clean("/"+filepath.ToSlash(dir)), "/")
+	if len(dir) == 0 {
+		return tree, nil
+	}
+
+	for _, name := range strings.Split(dir, "/") {
+		content, err := repo.readObjectOfType(tree, gitTree)
+		if err != nil {
+			return tree, err
+		}
+		entries, err := parseGitTree(content)
+		if err != nil {
+			return tree, err
+		}
+
+		found := false
+		for _, entry := range entries {
+			if entry.name == name && entry.isTree() {
+				tree, found = entry.id, true
+				break
+			}
+		}
+		if !found {
+			return tree, fmt.Errorf("folder %s not found in the repository", dir)
+		}
+	}
+	return tree, nil
+}
+
+// listGitFiles adds the regular files of the tree, recursively, to files
+func listGitFiles(repo *gitRepository, tree gitObjectID, prefix string, files *[]gitFile) error {
+	content, err := repo.readObjectOfType(tree, gitTree)
+	if err != nil {
+		return err
+	}
+	entries, err := parseGitTree(content)
+	if err != nil {
+		return fmt.Errorf("unable to read tree %s: %w", tree, err)
+	}
+
+	for _, entry := range entries {
+		entryPath := path.Join(prefix, entry.name)
+		switch {
+		case entry.isTree():
+			err = listGitFiles(repo, entry.id, entryPath, files)
+			if err != nil {
+				return err
+			}
+		case entry.isFile():
+			*files = append(*files, gitFile{
+				path: entryPath,
+				mode: entry.fileMode(),
+				id:   entry.id,
+			})
+		}
+	}
+	return nil
+}
+
+func (p *gitProvider) NextTemplate() (*pipeline.Template, error) {
+	for p.next < len(p.files) {
+		file := p.files[p.next]
+		p.next++
+
+		relativePath := filepath.FromSlash(file.path)
+		if p.filter != nil {
+			accepted, err := filters.AcceptData(p.filter, relativePath, p.data)
+			if err != nil {
+				return nil, err
+			}
+			if !accepted {
+				continue
+			}
+		}
+
+		content, err := p.repo.readObjectOfType(file.id, gitBlob)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", file.path, err)
+		}
+		return &pipeline.Template{
+			Reader: io.NopCloser(bytes.NewReader(content)),
+			Path:   relativePath,
+			Mode:   file.mode,
+		}, nil
+	}
+	return nil, io.EOF
+}
+
+// SetData sets the data passed to the filter, see pipeline.DataAwareTemplateProvider
+func (p *gitProvider) SetData(data map[string]interface{}) {
+	p.data = data
+}
+
+// Reopen returns a new provider that reads the same tree from the beginning
+func (p *gitProvider) Reopen() (pipeline.TemplateProvider, error) {
+	return &gitProvider{
+		repo:   p.repo,
+		filter: p.filter,
+		files:  p.files,
+	}, nil
+}
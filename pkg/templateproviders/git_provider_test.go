@@ -0,0 +1,345 @@
+package templateproviders
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/filters"
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// gitTestRepo is a repository created with the git command line
+type gitTestRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newGitTestRepo(t *testing.T) *gitTestRepo {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := &gitTestRepo{t: t, dir: t.TempDir()}
+	repo.git("init", "--quiet", "--initial-branch=main")
+	return repo
+}
+
+func (r *gitTestRepo) git(args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=author", "GIT_AUTHOR_EMAIL=author@example.com",
+		"GIT_COMMITTER_NAME=author", "GIT_COMMITTER_EMAIL=author@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s failed: %s\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func (r *gitTestRepo) writeFile(path string, content string, mode os.FileMode) {
+	fullPath := filepath.Join(r.dir, filepath.FromSlash(path))
+	assert.NoError(r.t, os.MkdirAll(filepath.Dir(fullPath), os.ModePerm))
+	assert.NoError(r.t, os.WriteFile(fullPath, []byte(content), mode))
+	assert.NoError(r.t, os.Chmod(fullPath, mode))
+}
+
+func (r *gitTestRepo) commit(message string) string {
+	r.git("add", "-A")
+	r.git("commit", "--quiet", "-m", message)
+	return r.git("rev-parse", "HEAD")
+}
+
+// gitTestTemplate is a template returned by the git provider
+type gitTestTemplate struct {
+	path    string
+	mode    os.FileMode
+	content string
+}
+
+func readGitTemplates(t *testing.T, p pipeline.TemplateProvider) []gitTestTemplate {
+	templates := make([]gitTestTemplate, 0)
+	for {
+		tpl, err := p.NextTemplate()
+		if errors.Is(err, io.EOF) {
+			return templates
+		}
+		assert.NoError(t, err)
+		content, err := io.ReadAll(tpl.Reader)
+		assert.NoError(t, err)
+		templates = append(templates, gitTestTemplate{path: tpl.Path, mode: tpl.Mode, content: string(content)})
+	}
+}
+
+func gitTemplatePaths(templates []gitTestTemplate) []string {
+	paths := make([]string, 0, len(templates))
+	for _, tpl := range templates {
+		paths = append(paths, tpl.path)
+	}
+	return paths
+}
+
+// createGitTestRepo creates a repository with two commits, a branch and tags,
+// and returns the ids of the commits
+func createGitTestRepo(t *testing.T) (*gitTestRepo, string, string) {
+	repo := newGitTestRepo(t)
+	large := strings.Repeat("some line of a large template\n", 200)
+	repo.writeFile("templates/README.md", large, 0644)
+	repo.writeFile("templates/scripts/run.sh", "some-script", 0755)
+	repo.writeFile("templates/b.txt", "b-v1", 0644)
+	repo.writeFile("other.txt", "other", 0644)
+	first := repo.commit("first")
+	repo.git("tag", "v1")
+	repo.git("tag", "-a", "v1-annotated", "-m", "annotated")
+
+	repo.writeFile("templates/README.md", large+"one more line\n", 0644)
+	repo.writeFile("templates/b.txt", "b-v2", 0644)
+	assert.NoError(t, os.Remove(filepath.Join(repo.dir, "templates", "scripts", "run.sh")))
+	second := repo.commit("second")
+	repo.git("branch", "develop", first)
+	return repo, first, second
+}
+
+func TestNewGitProvider(t *testing.T) {
+	repo, first, second := createGitTestRepo(t)
+	large := strings.Repeat("some line of a large template\n", 200)
+	firstTemplates := []gitTestTemplate{
+		{path: "README.md", mode: 0644, content: large},
+		{path: "b.txt", mode: 0644, content: "b-v1"},
+		{path: filepath.Join("scripts", "run.sh"), mode: 0755, content: "some-script"},
+	}
+	secondTemplates := []gitTestTemplate{
+		{path: "README.md", mode: 0644, content: large + "one more line\n"},
+		{path: "b.txt", mode: 0644, content: "b-v2"},
+	}
+	tests := []struct {
+		name string
+		opts GitProviderOptions
+		want []gitTestTemplate
+	}{
+		{
+			name: "Should read HEAD by default",
+			opts: GitProviderOptions{Dir: "templates"},
+			want: secondTemplates,
+		},
+		{
+			name: "Should read branch",
+			opts: GitProviderOptions{Ref: "develop", Dir: "templates"},
+			want: firstTemplates,
+		},
+		{
+			name: "Should read full reference",
+			opts: GitProviderOptions{Ref: "refs/heads/main", Dir: "templates"},
+			want: secondTemplates,
+		},
+		{
+			name: "Should read lightweight tag",
+			opts: GitProviderOptions{Ref: "v1", Dir: "templates"},
+			want: firstTemplates,
+		},
+		{
+			name: "Should read annotated tag",
+			opts: GitProviderOptions{Ref: "v1-annotated", Dir: "/templates/"},
+			want: firstTemplates,
+		},
+		{
+			name: "Should read commit",
+			opts: GitProviderOptions{Ref: first, Dir: "templates"},
+			want: firstTemplates,
+		},
+		{
+			name: "Should read abbreviated commit",
+			opts: GitProviderOptions{Ref: second[:8], Dir: "templates"},
+			want: secondTemplates,
+		},
+		{
+			name: "Should read subfolder",
+			opts: GitProviderOptions{Ref: "v1", Dir: "templates/scripts"},
+			want: []gitTestTemplate{{path: "run.sh", mode: 0755, content: "some-script"}},
+		},
+		{
+			name: "Should read root and apply filter",
+			opts: GitProviderOptions{Filter: &mockFilter{File: "txt", Include: true}},
+			want: []gitTestTemplate{
+				{path: "other.txt", mode: 0644, content: "other"},
+				{path: filepath.Join("templates", "b.txt"), mode: 0644, content: "b-v2"},
+			},
+		},
+	}
+	layouts := []struct {
+		name    string
+		prepare func(t *testing.T) string
+	}{
+		{
+			name:    "loose objects",
+			prepare: func(t *testing.T) string { return repo.dir },
+		},
+		{
+			name: "packed objects with offset deltas",
+			prepare: func(t *testing.T) string {
+				clone := filepath.Join(t.TempDir(), "clone")
+				repo.git("clone", "--quiet", "--no-local", repo.dir, clone)
+				cloneRepo := &gitTestRepo{t: t, dir: clone}
+				cloneRepo.git("fetch", "--quiet", "origin", "refs/tags/*:refs/tags/*", "develop:develop")
+				cloneRepo.git("repack", "-adf", "--quiet")
+				cloneRepo.git("pack-refs", "--all")
+				return clone
+			},
+		},
+		{
+			name: "bare repository with reference deltas",
+			prepare: func(t *testing.T) string {
+				bare := filepath.Join(t.TempDir(), "bare.git")
+				repo.git("clone", "--quiet", "--bare", "--no-local", repo.dir, bare)
+				bareRepo := &gitTestRepo{t: t, dir: bare}
+				bareRepo.git("-c", "repack.useDeltaBaseOffset=false", "-c", "pack.useDeltaBaseOffset=false", "repack", "-adf", "--quiet")
+				return bare
+			},
+		},
+	}
+	for _, layout := range layouts {
+		t.Run(layout.name, func(t *testing.T) {
+			repoPath := layout.prepare(t)
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					opts := tt.opts
+					opts.RepoPath = repoPath
+
+					got, err := NewGitProvider(opts)
+
+					assert.NoError(t, err)
+					assert.Equal(t, tt.want, readGitTemplates(t, got))
+				})
+			}
+		})
+	}
+}
+
+func TestNewGitProvider_errors(t *testing.T) {
+	repo, _, _ := createGitTestRepo(t)
+	tests := []struct {
+		name    string
+		opts    GitProviderOptions
+		wantErr error
+	}{
+		{
+			name:    "Should return error if path is not a repository",
+			opts:    GitProviderOptions{RepoPath: filepath.Join(repo.dir, "templates")},
+			wantErr: errors.New(filepath.Join(repo.dir, "templates") + " is not a git repository"),
+		},
+		{
+			name:    "Should return error if reference doesn't exist",
+			opts:    GitProviderOptions{RepoPath: repo.dir, Ref: "missing"},
+			wantErr: errors.New(`unable to resolve "missing" in ` + repo.dir + `: unknown revision "missing"`),
+		},
+		{
+			name:    "Should not read files outside refs",
+			opts:    GitProviderOptions{RepoPath: repo.dir, Ref: "../config"},
+			wantErr: errors.New(`unable to resolve "../config" in ` + repo.dir + `: unknown revision "../config"`),
+		},
+		{
+			name:    "Should return error if folder doesn't exist",
+			opts:    GitProviderOptions{RepoPath: repo.dir, Dir: "templates/missing"},
+			wantErr: errors.New("folder templates/missing not found in the repository"),
+		},
+		{
+			name:    "Should return error if folder is a file",
+			opts:    GitProviderOptions{RepoPath: repo.dir, Dir: "other.txt"},
+			wantErr: errors.New("folder other.txt not found in the repository"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewGitProvider(tt.opts)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func Test_gitProvider_worktree(t *testing.T) {
+	repo, _, _ := createGitTestRepo(t)
+	worktree := filepath.Join(t.TempDir(), "worktree")
+	repo.git("worktree", "add", "--quiet", worktree, "develop")
+
+	head, err := NewGitProvider(GitProviderOptions{RepoPath: worktree, Dir: "templates/scripts"})
+	assert.NoError(t, err)
+	main, err := NewGitProvider(GitProviderOptions{RepoPath: worktree, Ref: "main", Dir: "templates"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []gitTestTemplate{{path: "run.sh", mode: 0755, content: "some-script"}}, readGitTemplates(t, head))
+	assert.Equal(t, []string{"README.md", "b.txt"}, gitTemplatePaths(readGitTemplates(t, main)))
+}
+
+func Test_gitProvider_Reopen(t *testing.T) {
+	repo, _, _ := createGitTestRepo(t)
+	p, err := NewGitProvider(GitProviderOptions{RepoPath: repo.dir, Dir: "templates", Filter: filters.NewNoOpFilter()})
+	assert.NoError(t, err)
+	first, err := p.NextTemplate()
+	assert.NoError(t, err)
+
+	got, err := p.(*gitProvider).Reopen()
+
+	assert.NoError(t, err)
+	reopened, err := got.NextTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, first.Path, reopened.Path)
+	next, err := p.NextTemplate()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Path, next.Path)
+}
+
+func Test_gitProvider_NextTemplate_dataFilter(t *testing.T) {
+	repo, _, _ := createGitTestRepo(t)
+	filter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "scripts/", Condition: "{{ .scripts }}"})
+	assert.NoError(t, err)
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		want []string
+	}{
+		{
+			name: "Should return the templates accepted with the data",
+			data: map[string]interface{}{"scripts": true},
+			want: []string{"README.md", "b.txt", filepath.Join("scripts", "run.sh")},
+		},
+		{
+			name: "Should not return the templates rejected with the data",
+			data: map[string]interface{}{"scripts": false},
+			want: []string{"README.md", "b.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewGitProvider(GitProviderOptions{RepoPath: repo.dir, Ref: "develop", Dir: "templates", Filter: filter})
+			assert.NoError(t, err)
+			pipeline.SetTemplateProviderData(p, tt.data)
+
+			assert.Equal(t, tt.want, gitTemplatePaths(readGitTemplates(t, p)))
+		})
+	}
+}
+
+func Test_gitProvider_NextTemplate_ShouldReturnErrorIfTheFilterFails(t *testing.T) {
+	repo, _, _ := createGitTestRepo(t)
+	filter, err := filters.NewConditionFilter(filters.ConditionRule{Pattern: "*", Condition: "{{ index .list 5 }}"})
+	assert.NoError(t, err)
+	p, err := NewGitProvider(GitProviderOptions{RepoPath: repo.dir, Filter: filter})
+	assert.NoError(t, err)
+	pipeline.SetTemplateProviderData(p, map[string]interface{}{"list": []string{}})
+
+	got, err := p.NextTemplate()
+
+	assert.ErrorContains(t, err, "unable to evaluate the condition")
+	assert.Nil(t, got)
+}
@@ -0,0 +1,331 @@
+package templateproviders
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// gitMaxSymbolicRefs limits the chains of symbolic references, to detect
+// loops
+const gitMaxSymbolicRefs = 10
+
+// gitRepository reads objects and references of a local git repository, bare
+// or not
+type gitRepository struct {
+	dir       string // folder with the HEAD of the repository, or of the worktree
+	commonDir string // folder with the objects and the shared references
+	packs     []*gitPack
+}
+
+// openGitRepository opens the repository at path: its .git folder (or the
+// folder referenced by a .git file) if present, the path itself otherwise
+func openGitRepository(path string) (*gitRepository, error) {
+	dir, err := gitDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	commonDir, err := gitCommonDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	packFiles, err := filepath.Glob(filepath.Join(commonDir, "objects", "pack", "*.idx"))
+	if err != nil {
+		return nil, err
+	}
+	repo := &gitRepository{dir: dir, commonDir: commonDir}
+	for _, packFile := range packFiles {
+		pack, err := openGitPack(strings.TrimSuffix(packFile, ".idx"))
+		if err != nil {
+			return nil, err
+		}
+		repo.packs = append(repo.packs, pack)
+	}
+	return repo, nil
+}
+
+func gitDir(path string) (string, error) {
+	dotGit := filepath.Join(path, ".git")
+	info, err := os.Stat(dotGit)
+	if err == nil && info.IsDir() {
+		return dotGit, nil
+	}
+	if err == nil {
+		content, err := os.ReadFile(dotGit)
+		if err != nil {
+			return "", err
+		}
+		dir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: ")
+		if !ok {
+			return "", fmt.Errorf("invalid git file %s", dotGit)
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(path, dir)
+		}
+		return dir, nil
+	}
+
+	_, err = os.Stat(filepath.Join(path, "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("%s is not a git repository", path)
+	}
+	return path, nil
+}
+
+// gitCommonDir returns the folder referenced by the commondir file of a linked
+// worktree, or dir itself
+func gitCommonDir(dir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, "commondir"))
+	if errors.Is(err, os.ErrNotExist) {
+		return dir, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	commonDir := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(dir, commonDir)
+	}
+	return commonDir, nil
+}
+
+// readObject returns the type and the content of the object
+func (r *gitRepository) readObject(id gitObjectID) (gitObjectType, []byte, error) {
+	for _, pack := range r.packs {
+		if offset, ok := pack.offset(id); ok {
+			return pack.readObject(offset, r.readObject)
+		}
+	}
+
+	file, err := os.Open(r.loosePath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil, fmt.Errorf("object %s not found", id)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	defer file.Close()
+
+	zlibReader, err := zlib.NewReader(file)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read object %s: %w", id, err)
+	}
+	defer zlibReader.Close()
+	reader := bufio.NewReader(zlibReader)
+
+	header, err := reader.ReadString(0)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read object %s: %w", id, err)
+	}
+	typeName, sizeValue, _ := strings.Cut(strings.TrimSuffix(header, "\x00"), " ")
+	objType, err := parseGitObjectType(typeName)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read object %s: %w", id, err)
+	}
+	size, err := strconv.ParseInt(sizeValue, 10, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read object %s: invalid size %q", id, sizeValue)
+	}
+	err = checkGitObjectSize(size)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read object %s: %w", id, err)
+	}
+	content, err := readGitObjectContent(reader, size)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read object %s: %w", id, err)
+	}
+	return objType, content, nil
+}
+
+// readObjectOfType reads an object, failing if it is not of the expected type
+func (r *gitRepository) readObjectOfType(id gitObjectID, expected gitObjectType) ([]byte, error) {
+	objType, content, err := r.readObject(id)
+	if err != nil {
+		return nil, err
+	}
+	if objType != expected {
+		return nil, fmt.Errorf("object %s is a %s, not a %s", id, objType, expected)
+	}
+	return content, nil
+}
+
+func (r *gitRepository) loosePath(id gitObjectID) string {
+	hexID := id.String()
+	return filepath.Join(r.commonDir, "objects", hexID[:2], hexID[2:])
+}
+
+// resolveTree returns the tree of the commit, tag or tree identified by rev:
+// a reference (i.e. HEAD, a branch or a tag, by short or full name) or an
+// object id, also abbreviated
+func (r *gitRepository) resolveTree(rev string) (gitObjectID, error) {
+	id, err := r.resolve(rev)
+	if err != nil {
+		return id, err
+	}
+
+	for {
+		objType, content, err := r.readObject(id)
+		if err != nil {
+			return id, err
+		}
+		switch objType {
+		case gitTree:
+			return id, nil
+		case gitCommit:
+			tree, ok := gitHeader(content, "tree")
+			if !ok {
+				return id, fmt.Errorf("commit %s has no tree", id)
+			}
+			return parseGitObjectID(tree)
+		case gitTag:
+			object, ok := gitHeader(content, "object")
+			if !ok {
+				return id, fmt.Errorf("tag %s has no object", id)
+			}
+			id, err = parseGitObjectID(object)
+			if err != nil {
+				return id, err
+			}
+		default:
+			return id, fmt.Errorf("%s is a %s, not a commit", rev, objType)
+		}
+	}
+}
+
+// resolve returns the id of the object identified by rev, looking for
+// references in the same order of git rev-parse
+func (r *gitRepository) resolve(rev string) (gitObjectID, error) {
+	if id, err := parseGitObjectID(rev); err == nil {
+		return id, nil
+	}
+
+	names := []string{"refs/" + rev, "refs/tags/" + rev, "refs/heads/" + rev, "refs/remotes/" + rev}
+	if strings.HasPrefix(rev, "refs/") || isGitPseudoRef(rev) {
+		names = append([]string{rev}, names...)
+	}
+	for _, name := range names {
+		id, found, err := r.readRef(name, 0)
+		if err != nil {
+			return id, err
+		}
+		if found {
+			return id, nil
+		}
+	}
+
+	ids, err := r.withPrefix(strings.ToLower(rev))
+	if err != nil {
+		return gitObjectID{}, err
+	}
+	switch len(ids) {
+	case 1:
+		return ids[0], nil
+	case 0:
+		return gitObjectID{}, fmt.Errorf("unknown revision %q", rev)
+	}
+	return gitObjectID{}, fmt.Errorf("ambiguous revision %q", rev)
+}
+
+// isGitPseudoRef returns whether name is a reference like HEAD or ORIG_HEAD
+func isGitPseudoRef(name string) bool {
+	return strings.HasSuffix(name, "HEAD") && strings.Trim(name, "ABCDEFGHIJKLMNOPQRSTUVWXYZ_") == ""
+}
+
+// readRef reads a loose or packed reference, following symbolic ones
+func (r *gitRepository) readRef(name string, depth int) (gitObjectID, bool, error) {
+	if depth > gitMaxSymbolicRefs {
+		return gitObjectID{}, false, fmt.Errorf("too many levels of symbolic references for %q", name)
+	}
+
+	if strings.Contains(name, "..") {
+		// not a valid reference name, and it could point outside the repository
+		return gitObjectID{}, false, nil
+	}
+
+	// pseudo references are specific to the worktree, the other ones shared
+	dir := r.commonDir
+	if isGitPseudoRef(name) {
+		dir = r.dir
+	}
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	info, err := os.Stat(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return gitObjectID{}, false, err
+	}
+	if err != nil || info.IsDir() {
+		return r.readPackedRef(name)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return gitObjectID{}, false, err
+	}
+	value := strings.TrimSpace(string(content))
+	if target, ok := strings.CutPrefix(value, "ref: "); ok {
+		return r.readRef(target, depth+1)
+	}
+	id, err := parseGitObjectID(value)
+	return id, err == nil, err
+}
+
+func (r *gitRepository) readPackedRef(name string) (gitObjectID, bool, error) {
+	content, err := os.ReadFile(filepath.Join(r.commonDir, "packed-refs"))
+	if errors.Is(err, os.ErrNotExist) {
+		return gitObjectID{}, false, nil
+	}
+	if err != nil {
+		return gitObjectID{}, false, err
+	}
+
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		value, refName, ok := strings.Cut(string(line), " ")
+		if !ok || strings.HasPrefix(value, "#") || refName != name {
+			continue
+		}
+		id, err := parseGitObjectID(value)
+		return id, err == nil, err
+	}
+	return gitObjectID{}, false, nil
+}
+
+// withPrefix returns the ids of the objects that start with the hexadecimal
+// prefix, that must be at least 4 characters long
+func (r *gitRepository) withPrefix(prefix string) ([]gitObjectID, error) {
+	if len(prefix) < 4 || strings.Trim(prefix, "0123456789abcdef") != "" {
+		return nil, nil
+	}
+
+	found := make(map[gitObjectID]bool)
+	for _, pack := range r.packs {
+		for _, id := range pack.withPrefix(prefix) {
+			found[id] = true
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(r.commonDir, "objects", prefix[:2]))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), prefix[2:]) {
+			id, err := parseGitObjectID(prefix[:2] + entry.Name())
+			if err == nil {
+				found[id] = true
+			}
+		}
+	}
+
+	ids := make([]gitObjectID, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
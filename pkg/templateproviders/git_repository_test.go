@@ -0,0 +1,67 @@
+package templateproviders
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func zlibCompress(t *testing.T, content []byte) []byte {
+	var buffer bytes.Buffer
+	writer := zlib.NewWriter(&buffer)
+	_, err := writer.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func Test_gitRepository_readObject_corrupted(t *testing.T) {
+	id, err := parseGitObjectID("0123456789abcdef0123456789abcdef01234567")
+	assert.NoError(t, err)
+	tests := []struct {
+		name    string
+		object  string
+		wantErr error
+	}{
+		{
+			name:    "Should return error if the size is negative",
+			object:  "blob -1\x00some-content",
+			wantErr: errors.New("unable to read object " + id.String() + ": invalid object size -1"),
+		},
+		{
+			name:    "Should return error if the size is too big",
+			object:  "blob 1099511627776\x00some-content",
+			wantErr: errors.New("unable to read object " + id.String() + ": invalid object size 1099511627776"),
+		},
+		{
+			name:    "Should return error if the content is shorter than the size",
+			object:  "blob 100\x00some-content",
+			wantErr: errors.New("unable to read object " + id.String() + ": unexpected EOF"),
+		},
+		{
+			name:    "Should return error if the size is not a number",
+			object:  "blob x\x00some-content",
+			wantErr: errors.New("unable to read object " + id.String() + `: invalid size "x"`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &gitRepository{commonDir: t.TempDir()}
+			path := repo.loosePath(id)
+			assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+			assert.NoError(t, os.WriteFile(path, zlibCompress(t, []byte(tt.object)), 0644))
+
+			objType, content, err := repo.readObject(id)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, gitObjectType(0), objType)
+			assert.Nil(t, content)
+		})
+	}
+}
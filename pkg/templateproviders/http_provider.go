@@ -0,0 +1,151 @@
+package templateproviders
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+var userCacheDir = os.UserCacheDir
+
+type HTTPProviderOptions struct {
+	URL      string                 // URL of the zip or tar.gz archive with the templates
+	SHA256   string                 // Hex encoded SHA-256 digest of the archive, optionally prefixed by "sha256:"; required
+	Format   string                 // Format of the archive, "zip" or "tar.gz"; defaults to the one of the extension of the URL path
+	CacheDir string                 // Folder of the downloaded archives; defaults to go-scaffold/archives in the user cache folder
+	Client   *http.Client           // Client used to download the archive; defaults to http.DefaultClient
+	Archive  ArchiveProviderOptions // Options of the archive provider, i.e. the folder with the templates
+}
+
+// NewHTTPProvider creates a provider that reads the templates from an archive
+// downloaded from a URL. The archive is verified against the SHA-256 digest,
+// and cached on disk by digest: if the cache contains it, the archive is not
+// downloaded again, so that the provider works offline.
+func NewHTTPProvider(ctx context.Context, opts HTTPProviderOptions) (pipeline.TemplateProvider, error) {
+	digest := strings.ToLower(strings.TrimPrefix(opts.SHA256, "sha256:"))
+	if len(digest) != 2*sha256.Size || strings.Trim(digest, "0123456789abcdef") != "" {
+		return nil, fmt.Errorf("invalid SHA-256 digest %q for %s", opts.SHA256, opts.URL)
+	}
+
+	format, err := httpArchiveFormat(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheDir := opts.CacheDir
+	if len(cacheDir) == 0 {
+		userDir, err := userCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		cacheDir = filepath.Join(userDir, "go-scaffold", "archives")
+	}
+	archivePath := filepath.Join(cacheDir, digest+"."+format)
+
+	cachedDigest, err := fileSHA256(archivePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if cachedDigest != digest {
+		err = download(ctx, opts, cacheDir, archivePath, digest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewArchiveFileProvider(archivePath, opts.Archive)
+}
+
+// httpArchiveFormat returns the format of the archive, as extension of the
+// cached file
+func httpArchiveFormat(opts HTTPProviderOptions) (string, error) {
+	format := opts.Format
+	if len(format) == 0 {
+		archiveURL, err := url.Parse(opts.URL)
+		if err != nil {
+			return "", err
+		}
+		format = strings.ToLower(archiveURL.Path)
+	}
+
+	switch {
+	case format == "zip" || strings.HasSuffix(format, ".zip"):
+		return "zip", nil
+	case format == "tar.gz" || strings.HasSuffix(format, ".tar.gz"), format == "tgz" || strings.HasSuffix(format, ".tgz"):
+		return "tar.gz", nil
+	}
+	return "", fmt.Errorf("unsupported archive format for %s", opts.URL)
+}
+
+// download downloads the archive in a temporary file, and moves it to
+// archivePath if its digest matches the expected one
+func download(ctx context.Context, opts HTTPProviderOptions, cacheDir string, archivePath string, digest string) error {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, opts.URL, nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to download %s: %s", opts.URL, response.Status)
+	}
+
+	err = os.MkdirAll(cacheDir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(cacheDir, "download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), response.Body)
+	closeErr := file.Close()
+	if err != nil {
+		return fmt.Errorf("unable to download %s: %w", opts.URL, err)
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if actual != digest {
+		return fmt.Errorf("checksum mismatch for %s: expected sha256:%s, got sha256:%s", opts.URL, digest, actual)
+	}
+	return os.Rename(file.Name(), archivePath)
+}
+
+// fileSHA256 returns the hex encoded SHA-256 digest of the file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,206 @@
+package templateproviders
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func sha256Hex(content []byte) string {
+	digest := sha256.Sum256(content)
+	return hex.EncodeToString(digest[:])
+}
+
+// newArchiveServer serves the archives by path, counting the requests
+func newArchiveServer(t *testing.T, archives map[string][]byte) (*httptest.Server, *atomic.Int32) {
+	requests := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		content, ok := archives[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestNewHTTPProvider(t *testing.T) {
+	zipContent := createZip(t, archiveTestEntries)
+	tarGzContent := createTarGz(t, archiveTestEntries)
+	server, requests := newArchiveServer(t, map[string][]byte{
+		"/scaffold.zip":    zipContent,
+		"/scaffold.tar.gz": tarGzContent,
+		"/download":        tarGzContent,
+	})
+	archiveOpts := ArchiveProviderOptions{StripComponents: 1, Dir: "templates"}
+	want := []archiveTestTemplate{
+		{path: "README.md", mode: 0644, content: "some-readme"},
+		{path: filepath.Join("bin", "run.sh"), mode: 0755, content: "some-script"},
+	}
+	tests := []struct {
+		name string
+		opts HTTPProviderOptions
+		file string
+	}{
+		{
+			name: "Should download zip archive",
+			opts: HTTPProviderOptions{URL: server.URL + "/scaffold.zip", SHA256: sha256Hex(zipContent)},
+			file: sha256Hex(zipContent) + ".zip",
+		},
+		{
+			name: "Should download tar.gz archive with prefixed digest",
+			opts: HTTPProviderOptions{URL: server.URL + "/scaffold.tar.gz?token=some-token", SHA256: "sha256:" + sha256Hex(tarGzContent)},
+			file: sha256Hex(tarGzContent) + ".tar.gz",
+		},
+		{
+			name: "Should download archive with explicit format",
+			opts: HTTPProviderOptions{URL: server.URL + "/download", SHA256: sha256Hex(tarGzContent), Format: "tgz", Client: server.Client()},
+			file: sha256Hex(tarGzContent) + ".tar.gz",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			opts := tt.opts
+			opts.CacheDir = filepath.Join(t.TempDir(), "cache")
+			opts.Archive = archiveOpts
+
+			got, err := NewHTTPProvider(context.Background(), opts)
+
+			assert.NoError(t, err)
+			templates, err := readArchiveTemplates(t, got)
+			assert.NoError(t, err)
+			assert.Equal(t, want, templates)
+			assert.FileExists(t, filepath.Join(opts.CacheDir, tt.file))
+			assert.Equal(t, int32(1), requests.Load())
+
+			// cached archive
+			cached, err := NewHTTPProvider(context.Background(), opts)
+			assert.NoError(t, err)
+			templates, err = readArchiveTemplates(t, cached)
+			assert.NoError(t, err)
+			assert.Equal(t, want, templates)
+			assert.Equal(t, int32(1), requests.Load())
+
+			// reopened provider
+			reopened, err := pipeline.ReopenTemplateProvider(cached)
+			assert.NoError(t, err)
+			templates, err = readArchiveTemplates(t, reopened)
+			assert.NoError(t, err)
+			assert.Equal(t, want, templates)
+		})
+	}
+}
+
+func TestNewHTTPProvider_offline(t *testing.T) {
+	content := createZip(t, archiveTestEntries)
+	server, _ := newArchiveServer(t, map[string][]byte{"/scaffold.zip": content})
+	opts := HTTPProviderOptions{URL: server.URL + "/scaffold.zip", SHA256: sha256Hex(content), CacheDir: t.TempDir()}
+	_, err := NewHTTPProvider(context.Background(), opts)
+	assert.NoError(t, err)
+	server.Close()
+
+	got, err := NewHTTPProvider(context.Background(), opts)
+
+	assert.NoError(t, err)
+	templates, err := readArchiveTemplates(t, got)
+	assert.NoError(t, err)
+	assert.Len(t, templates, 3)
+}
+
+func TestNewHTTPProvider_corruptedCache(t *testing.T) {
+	content := createZip(t, archiveTestEntries)
+	server, requests := newArchiveServer(t, map[string][]byte{"/scaffold.zip": content})
+	cacheDir := t.TempDir()
+	cachedPath := filepath.Join(cacheDir, sha256Hex(content)+".zip")
+	assert.NoError(t, os.WriteFile(cachedPath, []byte("corrupted"), 0644))
+
+	got, err := NewHTTPProvider(context.Background(), HTTPProviderOptions{URL: server.URL + "/scaffold.zip", SHA256: sha256Hex(content), CacheDir: cacheDir})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, got)
+	assert.Equal(t, int32(1), requests.Load())
+	cached, err := os.ReadFile(cachedPath)
+	assert.NoError(t, err)
+	assert.Equal(t, content, cached)
+}
+
+func TestNewHTTPProvider_errors(t *testing.T) {
+	content := createZip(t, archiveTestEntries)
+	server, _ := newArchiveServer(t, map[string][]byte{"/scaffold.zip": content})
+	digest := sha256Hex(content)
+	otherDigest := sha256Hex([]byte("other"))
+	tests := []struct {
+		name    string
+		opts    HTTPProviderOptions
+		wantErr error
+	}{
+		{
+			name:    "Should return error if digest is missing",
+			opts:    HTTPProviderOptions{URL: server.URL + "/scaffold.zip"},
+			wantErr: errors.New(`invalid SHA-256 digest "" for ` + server.URL + "/scaffold.zip"),
+		},
+		{
+			name:    "Should return error if digest is not valid",
+			opts:    HTTPProviderOptions{URL: server.URL + "/scaffold.zip", SHA256: "sha512:" + digest},
+			wantErr: errors.New(`invalid SHA-256 digest "sha512:` + digest + `" for ` + server.URL + "/scaffold.zip"),
+		},
+		{
+			name:    "Should return error if format is not supported",
+			opts:    HTTPProviderOptions{URL: server.URL + "/scaffold.rar", SHA256: digest},
+			wantErr: errors.New("unsupported archive format for " + server.URL + "/scaffold.rar"),
+		},
+		{
+			name:    "Should return error if download fails",
+			opts:    HTTPProviderOptions{URL: server.URL + "/missing.zip", SHA256: digest},
+			wantErr: errors.New("unable to download " + server.URL + "/missing.zip: 404 Not Found"),
+		},
+		{
+			name:    "Should return error if checksum doesn't match",
+			opts:    HTTPProviderOptions{URL: server.URL + "/scaffold.zip", SHA256: otherDigest},
+			wantErr: errors.New("checksum mismatch for " + server.URL + "/scaffold.zip: expected sha256:" + otherDigest + ", got sha256:" + digest),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.CacheDir = t.TempDir()
+
+			got, err := NewHTTPProvider(context.Background(), opts)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Nil(t, got)
+			entries, err := os.ReadDir(opts.CacheDir)
+			assert.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestNewHTTPProvider_defaultCacheDir(t *testing.T) {
+	content := createZip(t, archiveTestEntries)
+	server, _ := newArchiveServer(t, map[string][]byte{"/scaffold.zip": content})
+	userDir := t.TempDir()
+	originalValue := userCacheDir
+	userCacheDir = func() (string, error) { return userDir, nil }
+	t.Cleanup(func() { userCacheDir = originalValue })
+
+	_, err := NewHTTPProvider(context.Background(), HTTPProviderOptions{URL: server.URL + "/scaffold.zip", SHA256: sha256Hex(content)})
+
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(userDir, "go-scaffold", "archives", sha256Hex(content)+".zip"))
+}
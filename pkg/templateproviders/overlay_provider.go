@@ -0,0 +1,177 @@
+package templateproviders
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+const (
+	// WhiteoutPrefix is the prefix of the name of the files that remove, from
+	// the lower layers of an overlay provider, the file (or folder) with the
+	// rest of the name, i.e. ".wh.README.md" removes "README.md"
+	WhiteoutPrefix = ".wh."
+
+	// OpaqueWhiteout is the name of the file that removes, from the lower
+	// layers of an overlay provider, all the files of its folder
+	OpaqueWhiteout = WhiteoutPrefix + WhiteoutPrefix + ".opq"
+)
+
+// OverlayLayer is a layer of an overlay provider
+type OverlayLayer struct {
+	Name     string                    // Name of the layer, set as Origin of its templates
+	Provider pipeline.TemplateProvider // Provider of the templates of the layer
+}
+
+type overlayProvider struct {
+	layers    []OverlayLayer
+	templates []*bufferedTemplate
+	loaded    bool
+	next      int
+}
+
+// NewOverlayProvider creates a provider that combines the templates of the
+// layers, in priority order: a template of a layer replaces the one with the
+// same path of the previous layers, and the whiteout files (see
+// WhiteoutPrefix and OpaqueWhiteout) remove the templates of the previous
+// layers. The Origin of each template is the name of its layer.
+//
+// The layers are read, and the templates buffered, on the first call to
+// NextTemplate; templates are returned sorted by path, like the ones of the
+// other providers of this package.
+func NewOverlayProvider(layers ...OverlayLayer) pipeline.TemplateProvider {
+	return &overlayProvider{
+		layers: layers,
+	}
+}
+
+func (p *overlayProvider) NextTemplate() (*pipeline.Template, error) {
+	if !p.loaded {
+		err := p.load()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.next >= len(p.templates) {
+		return nil, io.EOF
+	}
+	tpl := p.templates[p.next]
+	p.next++
+	return tpl.newTemplate(), nil
+}
+
+func (p *overlayProvider) load() error {
+	p.loaded = true
+
+	templates := make(map[string]*bufferedTemplate)
+	for _, layer := range p.layers {
+		layerTemplates, whiteouts, opaqueDirs, err := readLayer(layer)
+		if err != nil {
+			return err
+		}
+
+		for path := range templates {
+			if isRemoved(path, whiteouts, opaqueDirs) {
+				delete(templates, path)
+			}
+		}
+		for _, tpl := range layerTemplates {
+			templates[tpl.template.Path] = tpl
+		}
+	}
+
+	p.templates = make([]*bufferedTemplate, 0, len(templates))
+	for _, tpl := range templates {
+		p.templates = append(p.templates, tpl)
+	}
+	sortBufferedTemplates(p.templates)
+	return nil
+}
+
+// readLayer reads the templates of the layer, and returns them with the paths
+// removed by its whiteout files
+func readLayer(layer OverlayLayer) ([]*bufferedTemplate, []string, []string, error) {
+	templates := make([]*bufferedTemplate, 0)
+	whiteouts := make([]string, 0)
+	opaqueDirs := make([]string, 0)
+	for {
+		tpl, err := layer.Provider.NextTemplate()
+		if errors.Is(err, io.EOF) {
+			return templates, whiteouts, opaqueDirs, nil
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to read layer %s: %w", layer.Name, err)
+		}
+
+		buffered, err := readTemplate(tpl)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to read %s of layer %s: %w", tpl.Path, layer.Name, err)
+		}
+
+		dir, name := filepath.Split(tpl.Path)
+		switch {
+		case name == OpaqueWhiteout:
+			opaqueDirs = append(opaqueDirs, dir)
+		case strings.HasPrefix(name, WhiteoutPrefix):
+			whiteouts = append(whiteouts, filepath.Join(dir, strings.TrimPrefix(name, WhiteoutPrefix)))
+		default:
+			if len(layer.Name) > 0 {
+				buffered.template.Origin = layer.Name
+			}
+			templates = append(templates, buffered)
+		}
+	}
+}
+
+// isRemoved returns whether the path is removed by a whiteout: it is the
+// removed path or one of its files, or a file of an opaque folder
+func isRemoved(path string, whiteouts []string, opaqueDirs []string) bool {
+	for _, whiteout := range whiteouts {
+		if path == whiteout || strings.HasPrefix(path, whiteout+string(filepath.Separator)) {
+			return true
+		}
+	}
+	for _, dir := range opaqueDirs {
+		if strings.HasPrefix(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetData sets the data on the providers of the layers, see
+// pipeline.DataAwareTemplateProvider
+func (p *overlayProvider) SetData(data map[string]interface{}) {
+	for _, layer := range p.layers {
+		pipeline.SetTemplateProviderData(layer.Provider, data)
+	}
+}
+
+// Reopen returns a new provider with the reopened layers, see
+// pipeline.ReopenTemplateProvider
+func (p *overlayProvider) Reopen() (pipeline.TemplateProvider, error) {
+	layers := make([]OverlayLayer, 0, len(p.layers))
+	for _, layer := range p.layers {
+		provider, err := pipeline.ReopenTemplateProvider(layer.Provider)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, OverlayLayer{Name: layer.Name, Provider: provider})
+	}
+	return NewOverlayProvider(layers...), nil
+}
+
+// Close closes the providers of the layers, see
+// pipeline.CloseTemplateProvider
+func (p *overlayProvider) Close() error {
+	var errs []error
+	for _, layer := range p.layers {
+		errs = append(errs, pipeline.CloseTemplateProvider(layer.Provider))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,236 @@
+package templateproviders
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticProvider returns the templates with the specified paths and contents
+type staticProvider struct {
+	files    [][2]string
+	err      error
+	next     int
+	closed   int
+	closeErr error
+}
+
+func newStaticProvider(files ...[2]string) *staticProvider {
+	return &staticProvider{files: files}
+}
+
+func (p *staticProvider) NextTemplate() (*pipeline.Template, error) {
+	if p.next >= len(p.files) {
+		if p.err != nil {
+			return nil, p.err
+		}
+		return nil, io.EOF
+	}
+	file := p.files[p.next]
+	p.next++
+	return &pipeline.Template{
+		Path:   filepath.FromSlash(file[0]),
+		Mode:   0644,
+		Reader: io.NopCloser(strings.NewReader(file[1])),
+	}, nil
+}
+
+func (p *staticProvider) Close() error {
+	p.closed++
+	return p.closeErr
+}
+
+// overlayTestTemplate is a template returned by an overlay provider
+type overlayTestTemplate struct {
+	path    string
+	origin  string
+	content string
+}
+
+func readOverlayTemplates(t *testing.T, p pipeline.TemplateProvider) ([]overlayTestTemplate, error) {
+	templates := make([]overlayTestTemplate, 0)
+	for {
+		tpl, err := p.NextTemplate()
+		if errors.Is(err, io.EOF) {
+			return templates, nil
+		}
+		if err != nil {
+			return templates, err
+		}
+		content, err := io.ReadAll(tpl.Reader)
+		assert.NoError(t, err)
+		templates = append(templates, overlayTestTemplate{path: filepath.ToSlash(tpl.Path), origin: tpl.Origin, content: string(content)})
+	}
+}
+
+func Test_overlayProvider_NextTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		layers  func() []OverlayLayer
+		want    []overlayTestTemplate
+		wantErr error
+	}{
+		{
+			name: "Should return no templates if there are no layers",
+			layers: func() []OverlayLayer {
+				return nil
+			},
+			want: []overlayTestTemplate{},
+		},
+		{
+			name: "Should replace templates of the previous layers",
+			layers: func() []OverlayLayer {
+				return []OverlayLayer{
+					{Name: "base", Provider: newStaticProvider([2]string{"a.txt", "base-a"}, [2]string{"b.txt", "base-b"}, [2]string{"c.txt", "base-c"})},
+					{Name: "team", Provider: newStaticProvider([2]string{"d.txt", "team-d"}, [2]string{"b.txt", "team-b"}, [2]string{"a/a.txt", "team-a"})},
+					{Name: "service", Provider: newStaticProvider([2]string{"d.txt", "service-d"})},
+				}
+			},
+			want: []overlayTestTemplate{
+				{path: "a.txt", origin: "base", content: "base-a"},
+				{path: "a/a.txt", origin: "team", content: "team-a"},
+				{path: "b.txt", origin: "team", content: "team-b"},
+				{path: "c.txt", origin: "base", content: "base-c"},
+				{path: "d.txt", origin: "service", content: "service-d"},
+			},
+		},
+		{
+			name: "Should remove files and folders of the previous layers",
+			layers: func() []OverlayLayer {
+				return []OverlayLayer{
+					{Name: "base", Provider: newStaticProvider(
+						[2]string{"README.md", "base-readme"},
+						[2]string{"docs/a.md", "base-a"},
+						[2]string{"docs/b.md", "base-b"},
+						[2]string{"docs-extra.md", "base-extra"},
+						[2]string{"ci/build.yaml", "base-build"},
+					)},
+					{Name: "team", Provider: newStaticProvider(
+						[2]string{".wh.README.md", ""},
+						[2]string{".wh.docs", ""},
+						[2]string{"ci/.wh.build.yaml", ""},
+						[2]string{"ci/.wh.missing.yaml", ""},
+					)},
+				}
+			},
+			want: []overlayTestTemplate{
+				{path: "docs-extra.md", origin: "base", content: "base-extra"},
+			},
+		},
+		{
+			name: "Should remove all the files of opaque folders",
+			layers: func() []OverlayLayer {
+				return []OverlayLayer{
+					{Name: "base", Provider: newStaticProvider(
+						[2]string{"README.md", "base-readme"},
+						[2]string{"docs/a.md", "base-a"},
+						[2]string{"docs/sub/b.md", "base-b"},
+					)},
+					{Name: "team", Provider: newStaticProvider(
+						[2]string{"docs/.wh..wh..opq", ""},
+						[2]string{"docs/c.md", "team-c"},
+					)},
+				}
+			},
+			want: []overlayTestTemplate{
+				{path: "README.md", origin: "base", content: "base-readme"},
+				{path: "docs/c.md", origin: "team", content: "team-c"},
+			},
+		},
+		{
+			name: "Should not remove files of the same layer, and add files removed from previous layers",
+			layers: func() []OverlayLayer {
+				return []OverlayLayer{
+					{Name: "base", Provider: newStaticProvider([2]string{"a.txt", "base-a"}, [2]string{"b.txt", "base-b"})},
+					{Name: "team", Provider: newStaticProvider([2]string{"a.txt", "team-a"}, [2]string{".wh.a.txt", ""})},
+				}
+			},
+			want: []overlayTestTemplate{
+				{path: "a.txt", origin: "team", content: "team-a"},
+				{path: "b.txt", origin: "base", content: "base-b"},
+			},
+		},
+		{
+			name: "Should keep the origin of the templates of unnamed layers",
+			layers: func() []OverlayLayer {
+				inner := NewOverlayProvider(OverlayLayer{Name: "inner", Provider: newStaticProvider([2]string{"a.txt", "inner-a"})})
+				return []OverlayLayer{{Provider: inner}}
+			},
+			want: []overlayTestTemplate{
+				{path: "a.txt", origin: "inner", content: "inner-a"},
+			},
+		},
+		{
+			name: "Should propagate error of a layer",
+			layers: func() []OverlayLayer {
+				failing := newStaticProvider([2]string{"a.txt", "team-a"})
+				failing.err = errors.New("some-error")
+				return []OverlayLayer{
+					{Name: "base", Provider: newStaticProvider([2]string{"a.txt", "base-a"})},
+					{Name: "team", Provider: failing},
+				}
+			},
+			want:    []overlayTestTemplate{},
+			wantErr: errors.New("unable to read layer team: some-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewOverlayProvider(tt.layers()...)
+
+			got, err := readOverlayTemplates(t, p)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_overlayProvider_Reopen(t *testing.T) {
+	baseDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(baseDir, "a.txt"), []byte("base-a"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseDir, "b.txt"), []byte("base-b"), 0644))
+	teamDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(teamDir, "b.txt"), []byte("team-b"), 0644))
+	p := NewOverlayProvider(
+		OverlayLayer{Name: "base", Provider: NewFileSystemProvider(baseDir, nil)},
+		OverlayLayer{Name: "team", Provider: NewFileSystemProvider(teamDir, nil)},
+	)
+	want := []overlayTestTemplate{
+		{path: "a.txt", origin: "base", content: "base-a"},
+		{path: "b.txt", origin: "team", content: "team-b"},
+	}
+	first, err := readOverlayTemplates(t, p)
+	assert.NoError(t, err)
+
+	reopened, err := pipeline.ReopenTemplateProvider(p)
+
+	assert.NoError(t, err)
+	second, err := readOverlayTemplates(t, reopened)
+	assert.NoError(t, err)
+	assert.Equal(t, want, first)
+	assert.Equal(t, want, second)
+}
+
+func Test_overlayProvider_Close(t *testing.T) {
+	base := newStaticProvider([2]string{"a.txt", "base-a"})
+	team := &staticProvider{closeErr: errors.New("some-close-error")}
+	p := NewOverlayProvider(
+		OverlayLayer{Name: "base", Provider: base},
+		OverlayLayer{Name: "team", Provider: team},
+		OverlayLayer{Name: "dir", Provider: NewFileSystemProvider(t.TempDir(), nil)},
+	)
+
+	err := pipeline.CloseTemplateProvider(p)
+
+	assertutils.AssertEqualErrors(t, errors.New("some-close-error"), err)
+	assert.Equal(t, 1, base.closed)
+	assert.Equal(t, 1, team.closed)
+}
@@ -0,0 +1,135 @@
+package templateproviders
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// comparePaths compares two template paths in lexicographic order of their
+// slash separated form, so that the order is the same on all platforms, i.e.
+// "a.txt" comes before "a/b.txt"
+func comparePaths(a string, b string) int {
+	return strings.Compare(filepath.ToSlash(a), filepath.ToSlash(b))
+}
+
+// bufferedTemplate is a template with its content read in memory
+type bufferedTemplate struct {
+	template *pipeline.Template
+	content  []byte
+}
+
+// readTemplate reads the content of the template, closing its reader
+func readTemplate(tpl *pipeline.Template) (*bufferedTemplate, error) {
+	var content []byte
+	if tpl.Reader != nil {
+		var err error
+		content, err = io.ReadAll(tpl.Reader)
+		tpl.Reader.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	metadata := *tpl
+	metadata.Reader = nil
+	return &bufferedTemplate{
+		template: &metadata,
+		content:  content,
+	}, nil
+}
+
+// newTemplate returns a copy of the template, that reads the buffered content
+func (t *bufferedTemplate) newTemplate() *pipeline.Template {
+	result := *t.template
+	result.Reader = io.NopCloser(bytes.NewReader(t.content))
+	return &result
+}
+
+// sortBufferedTemplates sorts the templates by path, see comparePaths
+func sortBufferedTemplates(templates []*bufferedTemplate) {
+	sort.SliceStable(templates, func(i, j int) bool {
+		return comparePaths(templates[i].template.Path, templates[j].template.Path) < 0
+	})
+}
+
+type sortedProvider struct {
+	provider  pipeline.TemplateProvider
+	templates []*bufferedTemplate
+	loaded    bool
+	next      int
+}
+
+// NewSortedProvider creates a provider that returns the templates of provider
+// sorted by path, in lexicographic order of their slash separated form (i.e.
+// "a.txt" comes before "a/b.txt"). The providers of this package already
+// return the templates in this order, except the tar.gz one without the Sort
+// option; the sorted provider is meant for custom ones: it reads all the
+// templates, buffering their content, on the first call to NextTemplate.
+func NewSortedProvider(provider pipeline.TemplateProvider) pipeline.TemplateProvider {
+	return &sortedProvider{
+		provider: provider,
+	}
+}
+
+func (p *sortedProvider) NextTemplate() (*pipeline.Template, error) {
+	if !p.loaded {
+		p.loaded = true
+		err := p.load()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.next >= len(p.templates) {
+		return nil, io.EOF
+	}
+	tpl := p.templates[p.next]
+	p.next++
+	return tpl.newTemplate(), nil
+}
+
+func (p *sortedProvider) load() error {
+	for {
+		tpl, err := p.provider.NextTemplate()
+		if errors.Is(err, io.EOF) {
+			sortBufferedTemplates(p.templates)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		buffered, err := readTemplate(tpl)
+		if err != nil {
+			return err
+		}
+		p.templates = append(p.templates, buffered)
+	}
+}
+
+// SetData sets the data on the wrapped provider, see
+// pipeline.DataAwareTemplateProvider
+func (p *sortedProvider) SetData(data map[string]interface{}) {
+	pipeline.SetTemplateProviderData(p.provider, data)
+}
+
+// Reopen returns a new sorted provider of the reopened provider, see
+// pipeline.ReopenTemplateProvider
+func (p *sortedProvider) Reopen() (pipeline.TemplateProvider, error) {
+	provider, err := pipeline.ReopenTemplateProvider(p.provider)
+	if err != nil {
+		return nil, err
+	}
+	return NewSortedProvider(provider), nil
+}
+
+// Close closes the wrapped provider, see pipeline.CloseTemplateProvider
+func (p *sortedProvider) Close() error {
+	return pipeline.CloseTemplateProvider(p.provider)
+}
@@ -0,0 +1,99 @@
+package templateproviders
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+	"github.com/pasdam/go-utils/pkg/assertutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sortedProvider_NextTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider func() pipeline.TemplateProvider
+		want     []overlayTestTemplate
+		wantErr  error
+	}{
+		{
+			name: "Should return templates sorted by path",
+			provider: func() pipeline.TemplateProvider {
+				return newStaticProvider([2]string{"b/c.txt", "c"}, [2]string{"a/x.txt", "x"}, [2]string{"a.txt", "a"}, [2]string{"a-b.txt", "a-b"})
+			},
+			want: []overlayTestTemplate{
+				{path: "a-b.txt", content: "a-b"},
+				{path: "a.txt", content: "a"},
+				{path: "a/x.txt", content: "x"},
+				{path: "b/c.txt", content: "c"},
+			},
+		},
+		{
+			name: "Should keep the order of templates with the same path",
+			provider: func() pipeline.TemplateProvider {
+				return newStaticProvider([2]string{"b.txt", "first"}, [2]string{"a.txt", "a"}, [2]string{"b.txt", "second"})
+			},
+			want: []overlayTestTemplate{
+				{path: "a.txt", content: "a"},
+				{path: "b.txt", content: "first"},
+				{path: "b.txt", content: "second"},
+			},
+		},
+		{
+			name: "Should propagate error of the provider",
+			provider: func() pipeline.TemplateProvider {
+				failing := newStaticProvider([2]string{"a.txt", "a"})
+				failing.err = errors.New("some-error")
+				return failing
+			},
+			want:    []overlayTestTemplate{},
+			wantErr: errors.New("some-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewSortedProvider(tt.provider())
+
+			got, err := readOverlayTemplates(t, p)
+
+			assertutils.AssertEqualErrors(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_sortedProvider_Reopen(t *testing.T) {
+	content := createZip(t, archiveTestEntries)
+	zipProvider, err := NewZipProvider(bytes.NewReader(content), int64(len(content)), ArchiveProviderOptions{StripComponents: 1})
+	assert.NoError(t, err)
+	p := NewSortedProvider(zipProvider)
+	first, err := readOverlayTemplates(t, p)
+	assert.NoError(t, err)
+
+	reopened, err := pipeline.ReopenTemplateProvider(p)
+
+	assert.NoError(t, err)
+	second, err := readOverlayTemplates(t, reopened)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"other.txt", "templates/README.md", "templates/bin/run.sh"}, overlayTemplatePaths(first))
+	assert.Equal(t, first, second)
+}
+
+func Test_sortedProvider_Close(t *testing.T) {
+	provider := &staticProvider{closeErr: errors.New("some-close-error")}
+	p := NewSortedProvider(provider)
+
+	err := pipeline.CloseTemplateProvider(p)
+
+	assertutils.AssertEqualErrors(t, errors.New("some-close-error"), err)
+	assert.Equal(t, 1, provider.closed)
+}
+
+func overlayTemplatePaths(templates []overlayTestTemplate) []string {
+	paths := make([]string, 0, len(templates))
+	for _, tpl := range templates {
+		paths = append(paths, tpl.path)
+	}
+	return paths
+}
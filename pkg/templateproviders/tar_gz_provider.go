@@ -0,0 +1,85 @@
+package templateproviders
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+type tarGzProvider struct {
+	gzipReader *gzip.Reader
+	reader     *tar.Reader
+	opts       ArchiveProviderOptions
+	data       map[string]interface{}
+}
+
+// NewTarGzProvider creates a provider that reads the templates from a tar.gz
+// stream, in the order of its entries; the content of each template is read
+// when it is returned, so that the stream is read only once. Paths are
+// relative to the Dir folder of the options, like the ones of the filesystem
+// provider; folders and links are ignored. If the filter is a
+// filters.DataFilter, it receives the data of the run. If the Sort option is
+// set, the templates are returned sorted by path, see NewSortedProvider.
+func NewTarGzProvider(reader io.Reader, opts ArchiveProviderOptions) (pipeline.TemplateProvider, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var provider pipeline.TemplateProvider = &tarGzProvider{
+		gzipReader: gzipReader,
+		reader:     tar.NewReader(gzipReader),
+		opts:       opts,
+	}
+	if opts.Sort {
+		provider = NewSortedProvider(provider)
+	}
+	return provider, nil
+}
+
+func (p *tarGzProvider) NextTemplate() (*pipeline.Template, error) {
+	tpl, err := p.nextTemplate()
+	if err != nil {
+		p.gzipReader.Close()
+	}
+	return tpl, err
+}
+
+// nextTemplate reads the next template of the archive
+func (p *tarGzProvider) nextTemplate() (*pipeline.Template, error) {
+	for {
+		header, err := p.reader.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		relativePath, ok, err := p.opts.templatePath(header.Name, p.data)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		content, err := io.ReadAll(p.reader)
+		if err != nil {
+			return nil, err
+		}
+		return &pipeline.Template{
+			Reader: io.NopCloser(bytes.NewReader(content)),
+			Path:   relativePath,
+			Mode:   header.FileInfo().Mode().Perm(),
+		}, nil
+	}
+}
+
+// SetData sets the data passed to the filter, see pipeline.DataAwareTemplateProvider
+func (p *tarGzProvider) SetData(data map[string]interface{}) {
+	p.data = data
+}
@@ -0,0 +1,103 @@
+package templateproviders
+
+import (
+	"archive/zip"
+	"io"
+	"sort"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/pipeline"
+)
+
+// zipEntry is a file of a zip archive, with the path of its template
+type zipEntry struct {
+	file *zip.File
+	path string
+}
+
+type zipProvider struct {
+	reader  *zip.Reader
+	opts    ArchiveProviderOptions
+	data    map[string]interface{}
+	entries []zipEntry
+	listed  bool
+	next    int
+}
+
+// NewZipProvider creates a provider that reads the templates from a zip
+// archive, sorted by path like the ones of the filesystem provider. Paths are
+// relative to the Dir folder of the options; folders and symbolic links are
+// ignored. If the filter is a filters.DataFilter, it receives the data of the
+// run.
+func NewZipProvider(reader io.ReaderAt, size int64, opts ArchiveProviderOptions) (pipeline.TemplateProvider, error) {
+	zipReader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zipProvider{
+		reader: zipReader,
+		opts:   opts,
+	}, nil
+}
+
+func (p *zipProvider) NextTemplate() (*pipeline.Template, error) {
+	if !p.listed {
+		err := p.list()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.next >= len(p.entries) {
+		return nil, io.EOF
+	}
+	entry := p.entries[p.next]
+	p.next++
+
+	reader, err := entry.file.Open()
+	if err != nil {
+		return nil, err
+	}
+	return &pipeline.Template{
+		Reader: reader,
+		Path:   entry.path,
+		Mode:   entry.file.Mode().Perm(),
+	}, nil
+}
+
+// list selects the files of the archive to return, sorted by path
+func (p *zipProvider) list() error {
+	entries := make([]zipEntry, 0, len(p.reader.File))
+	for _, file := range p.reader.File {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		relativePath, ok, err := p.opts.templatePath(file.Name, p.data)
+		if err != nil {
+			return err
+		}
+		if ok {
+			entries = append(entries, zipEntry{file: file, path: relativePath})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return comparePaths(entries[i].path, entries[j].path) < 0 })
+	p.entries = entries
+	p.listed = true
+	return nil
+}
+
+// SetData sets the data passed to the filter, see pipeline.DataAwareTemplateProvider
+func (p *zipProvider) SetData(data map[string]interface{}) {
+	p.data = data
+}
+
+// Reopen returns a new provider that reads the same archive from the
+// beginning; the files are selected again, as the filter may depend on the
+// data of the run
+func (p *zipProvider) Reopen() (pipeline.TemplateProvider, error) {
+	return &zipProvider{
+		reader: p.reader,
+		opts:   p.opts,
+	}, nil
+}
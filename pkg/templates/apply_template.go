@@ -2,6 +2,7 @@ package templates
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"text/template"
 )
@@ -13,8 +14,38 @@ func applyTemplate(templateContent string, config interface{}, funcMap template.
 }
 
 func applyTemplateWithBase(templateContent string, config interface{}, funcMap template.FuncMap, templateAwareFuncGenerators TemplateAwareFuncMap, baseTemplate *template.Template) (io.Reader, error) {
+	return applyNamedTemplate("", templateContent, config, funcMap, templateAwareFuncGenerators, baseTemplate)
+}
+
+// applyNamedTemplate parses the template content with the specified name, so
+// that it is returned by Name() (i.e. to template-aware functions) and it is
+// used in parsing and execution errors.
+func applyNamedTemplate(name string, templateContent string, config interface{}, funcMap template.FuncMap, templateAwareFuncGenerators TemplateAwareFuncMap, baseTemplate *template.Template) (io.Reader, error) {
+	tpl, err := parseNamedTemplate(name, templateContent, funcMap, templateAwareFuncGenerators, baseTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var result bytes.Buffer
+	err = tpl.Execute(&result, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// parseNamedTemplate parses the template content with the specified name, in a
+// clone of the base template, if any. It returns an error if the name is
+// already used by a template of the base one, as the main template would
+// silently replace it.
+func parseNamedTemplate(name string, templateContent string, funcMap template.FuncMap, templateAwareFuncGenerators TemplateAwareFuncMap, baseTemplate *template.Template) (*template.Template, error) {
 	var tpl *template.Template
 	if baseTemplate != nil {
+		if name != "" && baseTemplate.Lookup(name) != nil {
+			return nil, fmt.Errorf("template %s: the name is already used by a common template", name)
+		}
+
 		// Clone the base template to inherit all associated templates (common templates)
 		var err error
 		tpl, err = baseTemplate.Clone()
@@ -22,9 +53,9 @@ func applyTemplateWithBase(templateContent string, config interface{}, funcMap t
 			return nil, err
 		}
 		// Create a new template within the cloned base to parse the new content
-		tpl = tpl.New("")
+		tpl = tpl.New(name)
 	} else {
-		tpl = template.New("")
+		tpl = template.New(name)
 	}
 
 	templateAwareFuncMap := make(template.FuncMap, len(templateAwareFuncGenerators))
@@ -34,16 +65,5 @@ func applyTemplateWithBase(templateContent string, config interface{}, funcMap t
 
 	tpl = tpl.Funcs(funcMap).Funcs(templateAwareFuncMap)
 
-	tpl, err := tpl.Parse(templateContent)
-	if err != nil {
-		return nil, err
-	}
-
-	var result bytes.Buffer
-	err = tpl.Execute(&result, config)
-	if err != nil {
-		return nil, err
-	}
-
-	return &result, nil
+	return tpl.Parse(templateContent)
 }
@@ -2,7 +2,6 @@ package templates
 
 import (
 	"io"
-	"log"
 	"text/template"
 )
 
@@ -21,16 +20,32 @@ func ProcessTemplateWithTemplateAware(reader io.Reader, data interface{}, funcMa
 // ProcessTemplateWithBaseTemplate processes the template using the specified data and a base template.
 // The base template can contain common templates that can be referenced from the main template.
 func ProcessTemplateWithBaseTemplate(reader io.Reader, data interface{}, funcMap template.FuncMap, templateAwareFuncGenerators TemplateAwareFuncMap, baseTemplate *template.Template) (io.Reader, error) {
+	return ProcessNamedTemplate("", reader, data, funcMap, templateAwareFuncGenerators, baseTemplate)
+}
+
+// ProcessNamedTemplate processes the template using the specified data and a
+// base template, like ProcessTemplateWithBaseTemplate, parsing it with the
+// specified name. The name is returned by the Name method of the template
+// passed to the template-aware functions, and it is included in parsing and
+// execution errors; the pipeline uses the path of the template.
+func ProcessNamedTemplate(name string, reader io.Reader, data interface{}, funcMap template.FuncMap, templateAwareFuncGenerators TemplateAwareFuncMap, baseTemplate *template.Template) (io.Reader, error) {
 	byteContent, err := readAll(reader)
 	if err != nil {
 		return nil, err
 	}
 
-	content, err := applyTemplateWithBase(string(byteContent), data, funcMap, templateAwareFuncGenerators, baseTemplate)
+	content, err := applyNamedTemplate(name, string(byteContent), data, funcMap, templateAwareFuncGenerators, baseTemplate)
 	if err != nil {
-		log.Println("Error while generating output file from template")
 		return nil, err
 	}
 
 	return content, nil
 }
+
+// ParseNamedTemplate parses the template content with the specified name and
+// base template, without executing it. The returned template can be executed
+// multiple times, also concurrently, so that the cost of parsing is paid only
+// once for templates processed more than once.
+func ParseNamedTemplate(name string, content string, funcMap template.FuncMap, templateAwareFuncGenerators TemplateAwareFuncMap, baseTemplate *template.Template) (*template.Template, error) {
+	return parseNamedTemplate(name, content, funcMap, templateAwareFuncGenerators, baseTemplate)
+}
@@ -153,3 +153,76 @@ func Test_ProcessTemplateWithBaseTemplate_success_shouldWorkWithTemplateAwareFun
 	assert.Nil(t, err)
 	assert.Equal(t, "Result: SNIPPET_CONTENT", string(readContent))
 }
+
+func Test_ProcessNamedTemplate_success_shouldExposeNameToTemplateAwareFunctions(t *testing.T) {
+	funcMap := template.FuncMap{}
+	templateAwareFnGen := TemplateAwareFuncMap{
+		"templateName": func(t *template.Template) any {
+			return func() string {
+				return t.Name()
+			}
+		},
+	}
+	baseTemplate := template.New("base").Funcs(funcMap)
+	baseTemplate, err := baseTemplate.New("snippet").Parse("SNIPPET_CONTENT")
+	assert.NoError(t, err)
+
+	reader, err := ProcessNamedTemplate("some/path/file.txt", strings.NewReader("{{ templateName }} {{ template \"snippet\" }}"), struct{}{}, funcMap, templateAwareFnGen, baseTemplate)
+
+	assert.Nil(t, err)
+	readContent, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, "some/path/file.txt SNIPPET_CONTENT", string(readContent))
+}
+
+func Test_ProcessNamedTemplate_fail_shouldIncludeNameInErrors(t *testing.T) {
+	funcMap := template.FuncMap{}
+
+	reader, err := ProcessNamedTemplate("some/path/file.txt", strings.NewReader("{{ .Missing.Field }}"), struct{}{}, funcMap, nil, nil)
+
+	assert.Nil(t, reader)
+	assert.ErrorContains(t, err, "template: some/path/file.txt:1:")
+}
+
+func Test_ParseNamedTemplate_success_shouldReturnReusableTemplate(t *testing.T) {
+	funcMap := template.FuncMap{
+		"Upper": strings.ToUpper,
+	}
+	baseTemplate := template.New("base").Funcs(funcMap)
+	baseTemplate, err := baseTemplate.New("greet").Parse("Hello {{ Upper . }}")
+	assert.NoError(t, err)
+
+	tpl, err := ParseNamedTemplate("some-name", "{{ template \"greet\" .Name }}", funcMap, nil, baseTemplate)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "some-name", tpl.Name())
+	for _, name := range []string{"world", "again"} {
+		var buf strings.Builder
+		assert.NoError(t, tpl.Execute(&buf, struct{ Name string }{Name: name}))
+		assert.Equal(t, "Hello "+strings.ToUpper(name), buf.String())
+	}
+}
+
+func Test_ParseNamedTemplate_fail_shouldReturnErrorIfTemplateIsInvalid(t *testing.T) {
+	tpl, err := ParseNamedTemplate("some-name", "{{ .Field }", template.FuncMap{}, nil, nil)
+
+	assert.ErrorContains(t, err, "template: some-name:1:")
+	assert.Nil(t, tpl)
+}
+
+func Test_ParseNamedTemplate_fail_shouldReturnErrorIfNameIsUsedByCommonTemplate(t *testing.T) {
+	baseTemplate := template.New("")
+	_, err := baseTemplate.New("header").Parse("HEADER_CONTENT")
+	assert.NoError(t, err)
+	_, err = baseTemplate.New("snippets").Parse("{{ define \"footer\" }}FOOTER_CONTENT{{ end }}")
+	assert.NoError(t, err)
+
+	for _, name := range []string{"header", "snippets", "footer"} {
+		t.Run(name, func(t *testing.T) {
+			tpl, err := ParseNamedTemplate(name, "some-content", template.FuncMap{}, nil, baseTemplate)
+
+			assert.Nil(t, tpl)
+			assert.EqualError(t, err, "template "+name+": the name is already used by a common template")
+		})
+	}
+}
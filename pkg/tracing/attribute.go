@@ -0,0 +1,27 @@
+package tracing
+
+// Attribute is a key-value pair describing a span
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String creates an attribute with a string value
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int creates an attribute with an int value
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int64 creates an attribute with an int64 value
+func Int64(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool creates an attribute with a bool value
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RecordedSpan is a span recorded by a Recorder
+type RecordedSpan struct {
+	// ID identifies the span in the recorder, starting from 1
+	ID int
+
+	// ParentID is the ID of the parent span, 0 for root spans
+	ParentID int
+
+	Name       string
+	Attributes []Attribute
+
+	// Err is the last error recorded, if any
+	Err error
+
+	Duration time.Duration
+
+	// Ended is true if End has been invoked
+	Ended bool
+}
+
+// Attribute returns the value of the last attribute with the specified key
+func (s RecordedSpan) Attribute(key string) (any, bool) {
+	for i := len(s.Attributes) - 1; i >= 0; i-- {
+		if s.Attributes[i].Key == key {
+			return s.Attributes[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Recorder is a Tracer that keeps the spans in memory, i.e. to inspect them in
+// tests. It is safe for concurrent use.
+type Recorder struct {
+	mutex sync.Mutex
+	spans []*recordedSpan
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+type recordedSpanKey struct{}
+
+func (r *Recorder) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	span := &recordedSpan{
+		recorder: r,
+		start:    time.Now(),
+		data: RecordedSpan{
+			ID:         len(r.spans) + 1,
+			Name:       name,
+			Attributes: append([]Attribute{}, attrs...),
+		},
+	}
+	if parent, ok := ctx.Value(recordedSpanKey{}).(*recordedSpan); ok && parent.recorder == r {
+		span.data.ParentID = parent.data.ID
+	}
+	r.spans = append(r.spans, span)
+
+	return context.WithValue(ctx, recordedSpanKey{}, span), span
+}
+
+// Spans returns a copy of the spans recorded so far, in the order they have
+// been started
+func (r *Recorder) Spans() []RecordedSpan {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	spans := make([]RecordedSpan, 0, len(r.spans))
+	for _, span := range r.spans {
+		data := span.data
+		data.Attributes = append([]Attribute{}, data.Attributes...)
+		spans = append(spans, data)
+	}
+	return spans
+}
+
+// SpansNamed returns the recorded spans with the specified name, in the order
+// they have been started
+func (r *Recorder) SpansNamed(name string) []RecordedSpan {
+	spans := make([]RecordedSpan, 0)
+	for _, span := range r.Spans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+type recordedSpan struct {
+	recorder *Recorder
+	start    time.Time
+	data     RecordedSpan
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	s.recorder.mutex.Lock()
+	defer s.recorder.mutex.Unlock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.recorder.mutex.Lock()
+	defer s.recorder.mutex.Unlock()
+	s.data.Err = err
+}
+
+func (s *recordedSpan) End() {
+	s.recorder.mutex.Lock()
+	defer s.recorder.mutex.Unlock()
+	if s.data.Ended {
+		return
+	}
+	s.data.Duration = time.Since(s.start)
+	s.data.Ended = true
+}
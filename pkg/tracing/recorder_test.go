@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+
+	ctx, root := recorder.Start(context.Background(), "root", String("some-key", "some-value"))
+	_, child := recorder.Start(ctx, "child")
+	child.SetAttributes(Int("some-int", 1), Int("some-int", 2))
+	child.RecordError(errors.New("some-error"))
+	child.RecordError(nil)
+	child.End()
+	_, sibling := recorder.Start(ctx, "child")
+	root.End()
+
+	spans := recorder.Spans()
+	assert.Len(t, spans, 3)
+
+	assert.Equal(t, 1, spans[0].ID)
+	assert.Equal(t, 0, spans[0].ParentID)
+	assert.Equal(t, "root", spans[0].Name)
+	assert.Equal(t, []Attribute{{Key: "some-key", Value: "some-value"}}, spans[0].Attributes)
+	assert.True(t, spans[0].Ended)
+	assert.NoError(t, spans[0].Err)
+
+	assert.Equal(t, 2, spans[1].ID)
+	assert.Equal(t, 1, spans[1].ParentID)
+	assert.Equal(t, errors.New("some-error"), spans[1].Err)
+	assert.True(t, spans[1].Ended)
+	value, ok := spans[1].Attribute("some-int")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	_, ok = spans[1].Attribute("missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, 3, spans[2].ID)
+	assert.Equal(t, 1, spans[2].ParentID)
+	assert.False(t, spans[2].Ended)
+	sibling.End()
+
+	assert.Len(t, recorder.SpansNamed("child"), 2)
+	assert.Empty(t, recorder.SpansNamed("missing"))
+}
+
+func TestRecorder_ShouldIgnoreSpansOfOtherRecorders(t *testing.T) {
+	ctx, _ := NewRecorder().Start(context.Background(), "other")
+	recorder := NewRecorder()
+
+	recorder.Start(ctx, "root")
+
+	assert.Equal(t, 0, recorder.Spans()[0].ParentID)
+}
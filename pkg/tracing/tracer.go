@@ -0,0 +1,49 @@
+package tracing
+
+import "context"
+
+// Tracer creates the spans that trace the stages of the processing. It mirrors
+// a minimal subset of the OpenTelemetry API, so that it can be implemented by
+// an adapter around an OpenTelemetry tracer, or by the in-memory Recorder in
+// tests.
+type Tracer interface {
+
+	// Start creates a span with the specified name and attributes, child of
+	// the span in ctx, if any, and returns a context containing it.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a single operation in a trace
+type Span interface {
+
+	// SetAttributes sets the specified attributes on the span
+	SetAttributes(attrs ...Attribute)
+
+	// RecordError records the error as the cause of the failure of the span,
+	// nil errors are ignored
+	RecordError(err error)
+
+	// End completes the span
+	End()
+}
+
+type tracerKey struct{}
+
+// ContextWithTracer returns a copy of ctx with the specified tracer, used by
+// Start to create the spans.
+func ContextWithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// TracerFromContext returns the tracer in ctx, or a no-op tracer if not set
+func TracerFromContext(ctx context.Context) Tracer {
+	if tracer, ok := ctx.Value(tracerKey{}).(Tracer); ok {
+		return tracer
+	}
+	return noOpTracer{}
+}
+
+// Start creates a span using the tracer in ctx, see Tracer.Start
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return TracerFromContext(ctx).Start(ctx, name, attrs...)
+}
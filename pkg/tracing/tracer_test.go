@@ -0,0 +1,38 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStart(t *testing.T) {
+	recorder := NewRecorder()
+	ctx := ContextWithTracer(context.Background(), recorder)
+
+	assert.Same(t, recorder, TracerFromContext(ctx))
+
+	ctx, span := Start(ctx, "some-span")
+	span.End()
+	_, child := Start(ctx, "some-child")
+	child.End()
+
+	spans := recorder.Spans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "some-span", spans[0].Name)
+	assert.Equal(t, spans[0].ID, spans[1].ParentID)
+}
+
+func TestStart_ShouldUseNoOpTracerIfNotSet(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Equal(t, NewNoOpTracer(), TracerFromContext(ctx))
+
+	gotCtx, span := Start(ctx, "some-span", String("some-key", "some-value"))
+	span.SetAttributes(Bool("some-bool", true))
+	span.RecordError(nil)
+	span.End()
+
+	assert.Equal(t, ctx, gotCtx)
+}
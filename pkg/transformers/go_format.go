@@ -0,0 +1,10 @@
+package transformers
+
+import (
+	"go/format"
+)
+
+// GoFormat formats Go source code like gofmt
+func GoFormat(path string, content []byte) ([]byte, error) {
+	return format.Source(content)
+}
@@ -0,0 +1,19 @@
+package transformers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoFormat(t *testing.T) {
+	got, err := GoFormat("main.go", []byte("package main\nfunc main( ) {\nx:=1\n_ = x}\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc main() {\n\tx := 1\n\t_ = x\n}\n", string(got))
+
+	got, err = GoFormat("main.go", []byte("package main\nfunc main( {\n"))
+
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,119 @@
+package transformers
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+)
+
+// GoImports returns a transformer that formats Go source code like gofmt, and
+// groups the imports like goimports: standard library packages first, then
+// third party packages, then the packages starting with any of the local
+// prefixes (i.e. the module path), each group sorted and separated by a blank
+// line. Import blocks containing comments on their own lines are only
+// formatted, to avoid moving the comments away from the imports they refer
+// to. Unused or missing imports are not fixed.
+func GoImports(localPrefixes ...string) collectors.Transformer {
+	return func(path string, content []byte) ([]byte, error) {
+		formatted, err := format.Source(content)
+		if err != nil {
+			return nil, err
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, formatted, parser.ImportsOnly|parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		lines := bytes.SplitAfter(formatted, []byte("\n"))
+		// replace the blocks starting from the last one, so that the line
+		// numbers of the previous ones are still valid
+		for i := len(file.Decls) - 1; i >= 0; i-- {
+			decl, ok := file.Decls[i].(*ast.GenDecl)
+			if !ok || decl.Tok != token.IMPORT || !decl.Lparen.IsValid() {
+				continue
+			}
+			first := fset.Position(decl.Lparen).Line // line numbers are 1-based
+			last := fset.Position(decl.Rparen).Line - 1
+			if last <= first || len(decl.Specs) == 0 { // empty, or on a single line
+				continue
+			}
+			block, ok := groupImports(lines[first:last], decl, fset, localPrefixes)
+			if !ok {
+				continue
+			}
+			lines = append(lines[:first], append(block, lines[last:]...)...)
+		}
+
+		return format.Source(bytes.Join(lines, nil))
+	}
+}
+
+// groupImports returns the lines of the import block grouped and sorted, the
+// second value is false if the block contains lines that are not imports
+func groupImports(lines [][]byte, decl *ast.GenDecl, fset *token.FileSet, localPrefixes []string) ([][]byte, bool) {
+	pathsByLine := make(map[int]string, len(decl.Specs))
+	for _, spec := range decl.Specs {
+		importSpec := spec.(*ast.ImportSpec)
+		path, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			return nil, false
+		}
+		pathsByLine[fset.Position(importSpec.Pos()).Line] = path
+	}
+	firstLine := fset.Position(decl.Lparen).Line + 1
+
+	type importLine struct {
+		path string
+		line []byte
+	}
+	groups := make([][]importLine, 3)
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		path, ok := pathsByLine[firstLine+i]
+		if !ok { // i.e. a comment, or multiple imports on the same line
+			return nil, false
+		}
+		group := importGroup(path, localPrefixes)
+		groups[group] = append(groups[group], importLine{path: path, line: line})
+	}
+
+	block := make([][]byte, 0, len(lines))
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		if len(block) > 0 {
+			block = append(block, []byte("\n"))
+		}
+		sort.SliceStable(group, func(i, j int) bool { return group[i].path < group[j].path })
+		for _, item := range group {
+			block = append(block, item.line)
+		}
+	}
+	return block, true
+}
+
+// importGroup returns 0 for the standard library packages, 1 for the third
+// party ones and 2 for the local ones
+func importGroup(path string, localPrefixes []string) int {
+	for _, prefix := range localPrefixes {
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return 2
+		}
+	}
+	if !strings.Contains(strings.Split(path, "/")[0], ".") {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,133 @@
+package transformers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoImports(t *testing.T) {
+	tests := []struct {
+		name          string
+		localPrefixes []string
+		content       string
+		want          string
+		wantErr       bool
+	}{
+		{
+			name:          "Should group and sort imports",
+			localPrefixes: []string{"example.com/project"},
+			content: `package main
+import (
+	"github.com/stretchr/testify/assert"
+	"example.com/project/pkg/a"
+	"os"
+
+	alias "example.com/other"
+	"fmt" // some comment
+	_ "example.com/project"
+)
+func main() {}
+`,
+			want: `package main
+
+import (
+	"fmt" // some comment
+	"os"
+
+	alias "example.com/other"
+	"github.com/stretchr/testify/assert"
+
+	_ "example.com/project"
+	"example.com/project/pkg/a"
+)
+
+func main() {}
+`,
+		},
+		{
+			name: "Should group multiple import blocks",
+			content: `package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+)
+
+import "fmt"
+
+import (
+	"strings"
+	"example.com/other"
+)
+`,
+			want: `package main
+
+import (
+	"os"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import "fmt"
+
+import (
+	"strings"
+
+	"example.com/other"
+)
+`,
+		},
+		{
+			name: "Should only format blocks with comments on their own lines",
+			content: `package main
+import (
+	// some comment
+	"github.com/stretchr/testify/assert"
+	"os"
+)
+`,
+			want: `package main
+
+import (
+	// some comment
+	"github.com/stretchr/testify/assert"
+	"os"
+)
+`,
+		},
+		{
+			name:    "Should leave an empty import block",
+			content: "package a\n\nimport ()\n",
+			want:    "package a\n\nimport ()\n",
+		},
+		{
+			name:    "Should format an import block written on one line",
+			content: "package a\n\nimport (\"os\")\n\nvar _ = os.Args\n",
+			want:    "package a\n\nimport (\n\t\"os\"\n)\n\nvar _ = os.Args\n",
+		},
+		{
+			name:    "Should sort an import block with multiple imports on one line",
+			content: "package a\n\nimport (\"os\"; \"fmt\")\n\nvar _, _ = os.Args, fmt.Sprint\n",
+			want:    "package a\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nvar _, _ = os.Args, fmt.Sprint\n",
+		},
+		{
+			name:    "Should return error if source is not valid",
+			content: "package main\nimport (\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GoImports(tt.localPrefixes...)("main.go", []byte(tt.content))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
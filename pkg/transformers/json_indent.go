@@ -0,0 +1,23 @@
+package transformers
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+)
+
+// JSONIndent returns a transformer that pretty-prints JSON documents, with
+// each level indented by indent, and a trailing new line. The order of the
+// keys is preserved.
+func JSONIndent(indent string) collectors.Transformer {
+	return func(path string, content []byte) ([]byte, error) {
+		var out bytes.Buffer
+		err := json.Indent(&out, bytes.TrimSpace(content), "", indent)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteByte('\n')
+		return out.Bytes(), nil
+	}
+}
@@ -0,0 +1,19 @@
+package transformers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONIndent(t *testing.T) {
+	got, err := JSONIndent("  ")("file.json", []byte(` {"b": [1, 2], "a": {"c": null}} `))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"b\": [\n    1,\n    2\n  ],\n  \"a\": {\n    \"c\": null\n  }\n}\n", string(got))
+
+	got, err = JSONIndent("  ")("file.json", []byte(`{"a": }`))
+
+	assert.EqualError(t, err, "invalid character '}' looking for beginning of value")
+	assert.Nil(t, got)
+}
@@ -0,0 +1,49 @@
+package transformers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/go-scaffold/go-sdk/v2/pkg/collectors"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLNormalize returns a transformer that re-encodes YAML documents with a
+// consistent style, each level indented by the specified number of spaces.
+// The order of the keys and the comments are preserved, multiple documents
+// are supported.
+func YAMLNormalize(indent int) collectors.Transformer {
+	return func(path string, content []byte) ([]byte, error) {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+
+		var out bytes.Buffer
+		encoder := yaml.NewEncoder(&out)
+		encoder.SetIndent(indent)
+		documents := 0
+		for {
+			var document yaml.Node
+			err := decoder.Decode(&document)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			err = encoder.Encode(&document)
+			if err != nil {
+				return nil, err
+			}
+			documents++
+		}
+		if documents == 0 {
+			return []byte{}, nil
+		}
+
+		err := encoder.Close()
+		if err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	}
+}
@@ -0,0 +1,50 @@
+package transformers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAMLNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Should normalize indentation preserving order and comments",
+			content: "b:\n     c: 1 # some comment\n     d: [1, 2]\na:    'x'\n",
+			want:    "b:\n  c: 1 # some comment\n  d: [1, 2]\na: 'x'\n",
+		},
+		{
+			name:    "Should normalize multiple documents",
+			content: "a:    1\n---\nb:\n      - 1\n      - 2\n",
+			want:    "a: 1\n---\nb:\n  - 1\n  - 2\n",
+		},
+		{
+			name:    "Should return empty content for empty documents",
+			content: "",
+			want:    "",
+		},
+		{
+			name:    "Should return error if content is not valid",
+			content: "a: [1\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := YAMLNormalize(2)("file.yaml", []byte(tt.content))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
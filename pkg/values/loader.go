@@ -1,8 +1,10 @@
 package values
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 	"github.com/pasdam/go-template-map-loader/pkg/tm"
 )
 
@@ -46,6 +48,34 @@ func NewLoaderWithValues(manifestPrefix, manifestBasename, valuesPrefix, valuesB
 }
 
 func (l *Loader) LoadYAMLs(manifestDir string, additionalValueFiles []string) (map[string]interface{}, error) {
+	return l.LoadYAMLsContext(context.Background(), manifestDir, additionalValueFiles)
+}
+
+// LoadYAMLsContext loads the YAML files like LoadYAMLs, tracing the loading
+// with the tracer in ctx, if any (see tracing.ContextWithTracer)
+func (l *Loader) LoadYAMLsContext(ctx context.Context, manifestDir string, additionalValueFiles []string) (map[string]interface{}, error) {
+	return l.LoadYAMLsWithOverridesContext(ctx, manifestDir, additionalValueFiles, Overrides{})
+}
+
+// LoadYAMLsWithOverrides loads the YAML files like LoadYAMLs, and sets the
+// overrides on top of the merged values (their paths are relative to the
+// values, i.e. "image.tag" for .Values.image.tag), see Overrides.Apply
+func (l *Loader) LoadYAMLsWithOverrides(manifestDir string, additionalValueFiles []string, overrides Overrides) (map[string]interface{}, error) {
+	return l.LoadYAMLsWithOverridesContext(context.Background(), manifestDir, additionalValueFiles, overrides)
+}
+
+// LoadYAMLsWithOverridesContext loads the YAML files with the overrides like
+// LoadYAMLsWithOverrides, tracing the loading like LoadYAMLsContext
+func (l *Loader) LoadYAMLsWithOverridesContext(ctx context.Context, manifestDir string, additionalValueFiles []string, overrides Overrides) (_ map[string]interface{}, err error) {
+	_, span := tracing.Start(ctx, "values.LoadYAMLs",
+		tracing.String("manifestDir", manifestDir),
+		tracing.Int("additionalValueFiles", len(additionalValueFiles)),
+	)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	manifestPath, err := GetYamlPath(manifestDir, l.manifestBasename)
 	if err != nil {
 		return nil, fmt.Errorf("an error occurred while getting the manifest path: %s", err.Error())
@@ -63,10 +93,14 @@ func (l *Loader) LoadYAMLs(manifestDir string, additionalValueFiles []string) (m
 	valuesPaths := make([]string, 0, len(additionalValueFiles)+1)
 	valuesPaths = append(valuesPaths, valuesPath)
 	valuesPaths = append(valuesPaths, additionalValueFiles...)
-	data, err := LoadYamlFilesWithPrefix(l.valuesPrefix, valuesPaths...)
+	data, err := LoadYamlFilesWithPrefix("", valuesPaths...)
 	if err != nil {
 		return nil, fmt.Errorf("error while loading data: %s", err.Error())
 	}
+	err = overrides.Apply(data)
+	if err != nil {
+		return nil, fmt.Errorf("error while applying the overrides: %s", err.Error())
+	}
 
-	return tm.MergeMaps(manifest, data), nil
+	return tm.MergeMaps(manifest, tm.WithPrefix(l.valuesPrefix, data)), nil
 }
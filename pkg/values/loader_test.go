@@ -1,11 +1,13 @@
 package values
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/go-scaffold/go-sdk/v2/pkg/tracing"
 	"github.com/pasdam/go-utils/pkg/filetestutils"
 	"github.com/stretchr/testify/assert"
 )
@@ -216,3 +218,55 @@ func TestLoader_LoadYAMLs(t *testing.T) {
 		})
 	}
 }
+
+func TestLoader_LoadYAMLsContext(t *testing.T) {
+	dir := filetestutils.TempDir(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Manifest.yml"), []byte("mk1: mv1\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "values.yml"), []byte("vk1: vv1\n"), 0644))
+	recorder := tracing.NewRecorder()
+	ctx := tracing.ContextWithTracer(context.Background(), recorder)
+
+	got, err := NewLoader().LoadYAMLsContext(ctx, dir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Manifest": map[string]interface{}{"mk1": "mv1"},
+		"Values":   map[string]interface{}{"vk1": "vv1"},
+	}, got)
+
+	_, err = NewLoader().LoadYAMLsContext(ctx, "not_existing_dir", nil)
+	assert.Error(t, err)
+
+	spans := recorder.SpansNamed("values.LoadYAMLs")
+	assert.Len(t, spans, 2)
+	manifestDir, _ := spans[0].Attribute("manifestDir")
+	assert.Equal(t, dir, manifestDir)
+	assert.NoError(t, spans[0].Err)
+	assert.True(t, spans[0].Ended)
+	assert.Equal(t, err, spans[1].Err)
+}
+
+func TestLoader_LoadYAMLsWithOverrides(t *testing.T) {
+	dir := filetestutils.TempDir(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Manifest.yml"), []byte("name: manifest\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "values.yml"), []byte("image:\n  repository: nginx\n  tag: latest\n"), 0644))
+	mockEnviron(t, []string{"APP_IMAGE__TAG=env", "APP_REPLICAS=2"})
+
+	got, err := NewLoaderWithValues("Manifest", "Manifest", "Config.Values", "values").LoadYAMLsWithOverrides(dir, nil, Overrides{
+		Set:       []string{"image.tag=1.2.3"},
+		EnvPrefix: "APP_",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Manifest": map[string]interface{}{"name": "manifest"},
+		"Config": map[string]interface{}{
+			"Values": map[string]interface{}{
+				"image":    map[string]interface{}{"repository": "nginx", "tag": "1.2.3"},
+				"replicas": 2,
+			},
+		},
+	}, got)
+
+	_, err = NewLoader().LoadYAMLsWithOverrides(dir, nil, Overrides{Set: []string{"image.tag"}})
+	assert.EqualError(t, err, `error while applying the overrides: unable to parse "image.tag" at position 9: missing value`)
+}
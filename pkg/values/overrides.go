@@ -0,0 +1,147 @@
+package values
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var environ = os.Environ
+
+// Overrides are values set on top of the ones loaded from the files, like the
+// --set flags of Helm
+type Overrides struct {
+	Set       []string // Assignments like "a.b[0].c=value,d={x,y}", with values converted to booleans and integers, see SetValues
+	SetString []string // Assignments like Set, whose values are always strings, see SetStringValues
+	SetFile   []string // Assignments like Set, whose values are paths of files to read, see SetFileValues
+	EnvPrefix string   // Prefix of the environment variables that set values, i.e. "APP_" for APP_IMAGE__TAG (image.tag), see SetEnvValues; disabled if empty
+}
+
+// Apply sets the overrides in values (that must not be nil), in increasing
+// order of precedence: environment variables, Set, SetString and SetFile. The
+// assignments of each field are applied in order, so the last one wins. If an
+// override is not valid, values is not modified.
+func (o Overrides) Apply(values map[string]interface{}) error {
+	return updateValues(values, o.apply)
+}
+
+func (o Overrides) apply(values map[string]interface{}) error {
+	if len(o.EnvPrefix) > 0 {
+		err := SetEnvValues(values, o.EnvPrefix, environ())
+		if err != nil {
+			return err
+		}
+	}
+
+	setters := []struct {
+		assignments []string
+		set         func(map[string]interface{}, string) error
+	}{
+		{o.Set, SetValues},
+		{o.SetString, SetStringValues},
+		{o.SetFile, SetFileValues},
+	}
+	for _, setter := range setters {
+		for _, assignments := range setter.assignments {
+			err := setter.set(values, assignments)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SetEnvValues sets in values (that must not be nil) the environment variables
+// (in the "NAME=value" form of os.Environ) whose name starts with prefix: the
+// rest of the name is the path of the value, with "__" separating the keys,
+// i.e. APP_IMAGE__TAG sets image.tag for the prefix "APP_". Keys match the
+// existing ones regardless of the case, and are lowercase otherwise; numeric
+// keys are indexes of existing lists. Values are converted like in SetValues.
+// The variables are applied sorted by name; if one is not valid, values is not
+// modified.
+func SetEnvValues(values map[string]interface{}, prefix string, environment []string) error {
+	return updateValues(values, func(values map[string]interface{}) error {
+		return setEnvValues(values, prefix, environment)
+	})
+}
+
+func setEnvValues(values map[string]interface{}, prefix string, environment []string) error {
+	variables := append([]string{}, environment...)
+	sort.Strings(variables)
+
+	for _, variable := range variables {
+		name, value, _ := strings.Cut(variable, "=")
+		path, ok := strings.CutPrefix(name, prefix)
+		if !ok || len(path) == 0 {
+			continue
+		}
+
+		segments, err := envPath(values, strings.Split(path, "__"))
+		if err != nil {
+			return fmt.Errorf("invalid environment variable %s: %w", name, err)
+		}
+		typed := typedValue(value)
+		setPath(values, segments, typed, typed == nil)
+	}
+	return nil
+}
+
+// envPath resolves the keys of an environment variable against the existing
+// values
+func envPath(values map[string]interface{}, keys []string) ([]pathSegment, error) {
+	segments := make([]pathSegment, 0, len(keys))
+	var current interface{} = values
+	for _, key := range keys {
+		if len(key) == 0 {
+			return nil, fmt.Errorf("empty key")
+		}
+
+		if list, ok := current.([]interface{}); ok {
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index > maxListIndex {
+				return nil, fmt.Errorf("invalid index %q", key)
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			current = nil
+			if index < len(list) {
+				current = list[index]
+			}
+			continue
+		}
+
+		resolved := strings.ToLower(key)
+		var next interface{}
+		if values, ok := current.(map[string]interface{}); ok {
+			if existing, found := matchKey(values, key); found {
+				resolved = existing
+			}
+			next = values[resolved]
+		}
+		segments = append(segments, pathSegment{key: resolved})
+		current = next
+	}
+	return segments, nil
+}
+
+// matchKey returns the key of values equal to key, or the first one (in
+// lexicographic order) equal regardless of the case
+func matchKey(values map[string]interface{}, key string) (string, bool) {
+	if _, ok := values[key]; ok {
+		return key, true
+	}
+
+	matches := make([]string, 0)
+	for existing := range values {
+		if strings.EqualFold(existing, key) {
+			matches = append(matches, existing)
+		}
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+	sort.Strings(matches)
+	return matches[0], true
+}
@@ -0,0 +1,195 @@
+package values
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverrides_Apply(t *testing.T) {
+	tests := []struct {
+		name        string
+		overrides   Overrides
+		environment []string
+		readErr     error
+		want        map[string]interface{}
+		wantErr     string
+	}{
+		{
+			name: "Should apply the overrides in order of precedence",
+			overrides: Overrides{
+				Set:       []string{"a=set,b=set,c=set", "d=42"},
+				SetString: []string{"b=set-string,c=set-string", "e=42"},
+				SetFile:   []string{"c=some-file"},
+				EnvPrefix: "APP_",
+			},
+			environment: []string{"APP_A=env", "APP_F=env", "OTHER_G=env"},
+			want: map[string]interface{}{
+				"a": "set",
+				"b": "set-string",
+				"c": "content of some-file",
+				"d": 42,
+				"e": "42",
+				"f": "env",
+				"g": "file",
+			},
+		},
+		{
+			name:        "Should ignore the environment without prefix",
+			overrides:   Overrides{},
+			environment: []string{"A=env"},
+			want:        map[string]interface{}{"g": "file"},
+		},
+		{
+			name:      "Should return the errors of Set",
+			overrides: Overrides{Set: []string{"a"}},
+			wantErr:   `unable to parse "a" at position 1: missing value`,
+		},
+		{
+			name:      "Should return the errors of SetString",
+			overrides: Overrides{SetString: []string{"a"}},
+			wantErr:   `unable to parse "a" at position 1: missing value`,
+		},
+		{
+			name:      "Should return the errors of SetFile",
+			overrides: Overrides{SetFile: []string{"a=some-file"}},
+			readErr:   errors.New("some-read-error"),
+			wantErr:   "some-read-error",
+		},
+		{
+			name: "Should not apply any override if one is not valid",
+			overrides: Overrides{
+				Set:       []string{"a=set", "b=set"},
+				SetString: []string{"c=set-string"},
+				SetFile:   []string{"d=some-file"},
+				EnvPrefix: "APP_",
+			},
+			environment: []string{"APP_E=env"},
+			readErr:     errors.New("some-read-error"),
+			wantErr:     "some-read-error",
+		},
+		{
+			name:        "Should return the errors of the environment",
+			overrides:   Overrides{EnvPrefix: "APP_"},
+			environment: []string{"APP_A____B=env"},
+			wantErr:     "invalid environment variable APP_A____B: empty key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockReadFile(t, tt.readErr)
+			mockEnviron(t, tt.environment)
+			values := map[string]interface{}{"g": "file"}
+
+			err := tt.overrides.Apply(values)
+
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Equal(t, map[string]interface{}{"g": "file"}, values)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, values)
+		})
+	}
+}
+
+func TestSetEnvValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      map[string]interface{}
+		environment []string
+		want        map[string]interface{}
+		wantErr     string
+	}{
+		{
+			name:        "Should set nested lowercase keys",
+			values:      map[string]interface{}{},
+			environment: []string{"APP_IMAGE__TAG=1.2.3", "APP_REPLICAS=3", "APP_DEBUG=true"},
+			want: map[string]interface{}{
+				"image":    map[string]interface{}{"tag": "1.2.3"},
+				"replicas": 3,
+				"debug":    true,
+			},
+		},
+		{
+			name:        "Should match the existing keys regardless of the case",
+			values:      map[string]interface{}{"projectName": "old", "Image": map[string]interface{}{"pullPolicy": "Always"}},
+			environment: []string{"APP_PROJECTNAME=new", "APP_IMAGE__PULLPOLICY=Never"},
+			want:        map[string]interface{}{"projectName": "new", "Image": map[string]interface{}{"pullPolicy": "Never"}},
+		},
+		{
+			name:        "Should prefer the exact key",
+			values:      map[string]interface{}{"name": "a", "Name": "b", "NAME": "c"},
+			environment: []string{"APP_Name=x"},
+			want:        map[string]interface{}{"name": "a", "Name": "x", "NAME": "c"},
+		},
+		{
+			name:        "Should set the elements of existing lists",
+			values:      map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": 80}}},
+			environment: []string{"APP_PORTS__0__PORT=8080", "APP_PORTS__1__PORT=9090"},
+			want: map[string]interface{}{"ports": []interface{}{
+				map[string]interface{}{"port": 8080},
+				map[string]interface{}{"port": 9090},
+			}},
+		},
+		{
+			name:        "Should remove a key set to null",
+			values:      map[string]interface{}{"a": "x", "b": "y"},
+			environment: []string{"APP_A=null"},
+			want:        map[string]interface{}{"b": "y"},
+		},
+		{
+			name:        "Should apply the variables sorted by name",
+			values:      map[string]interface{}{},
+			environment: []string{"APP_A__B=nested", "APP_A=scalar"},
+			want:        map[string]interface{}{"a": map[string]interface{}{"b": "nested"}},
+		},
+		{
+			name:        "Should ignore the variables without prefix or path",
+			values:      map[string]interface{}{},
+			environment: []string{"APP_=x", "OTHER=y", "NOVALUE"},
+			want:        map[string]interface{}{},
+		},
+		{
+			name:        "Should return an error if an index is invalid",
+			values:      map[string]interface{}{"list": []interface{}{}},
+			environment: []string{"APP_LIST__X=1"},
+			wantErr:     `invalid environment variable APP_LIST__X: invalid index "X"`,
+		},
+		{
+			name:        "Should not set any value if a variable is not valid",
+			values:      map[string]interface{}{"image": map[string]interface{}{"tag": "1.0.0"}, "list": []interface{}{}},
+			environment: []string{"APP_IMAGE__TAG=1.2.3", "APP_LIST__-1=x"},
+			wantErr:     `invalid environment variable APP_LIST__-1: invalid index "-1"`,
+		},
+		{
+			name:        "Should not create the parents of a removed key",
+			values:      map[string]interface{}{"b": "y"},
+			environment: []string{"APP_A__B=null"},
+			want:        map[string]interface{}{"b": "y"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := copyValue(tt.values)
+
+			err := SetEnvValues(tt.values, "APP_", tt.environment)
+
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Equal(t, original, tt.values)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.values)
+		})
+	}
+}
+
+func mockEnviron(t *testing.T, environment []string) {
+	originalValue := environ
+	environ = func() []string { return environment }
+	t.Cleanup(func() { environ = originalValue })
+}
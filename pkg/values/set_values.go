@@ -0,0 +1,316 @@
+package values
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maxListIndex limits the indexes of the lists in the paths, to avoid
+// allocating huge lists
+const maxListIndex = 65536
+
+var readFile = os.ReadFile
+
+// setValueType defines how the values of the assignments are interpreted
+type setValueType int
+
+const (
+	typedSetValue  setValueType = iota // booleans, integers and null are converted
+	stringSetValue                     // values are strings
+	fileSetValue                       // values are paths of files, whose content is set
+)
+
+// pathSegment is a map key or a list index of a path
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// SetValues parses Helm-like assignments, i.e. "a.b[0].c=value,d={x,y}", and
+// sets them in values (that must not be nil), creating or replacing the maps
+// and lists along the paths. Values are converted to booleans and integers,
+// "null" removes the key, and "{x,y}" is a list. A "\" escapes the next
+// character, i.e. "\." in keys and "\," in values. If an assignment is not
+// valid, values is not modified.
+func SetValues(values map[string]interface{}, assignments string) error {
+	return (&setParser{input: assignments, valueType: typedSetValue}).parse(values)
+}
+
+// SetStringValues sets the assignments like SetValues, without converting the
+// values: they are always strings
+func SetStringValues(values map[string]interface{}, assignments string) error {
+	return (&setParser{input: assignments, valueType: stringSetValue}).parse(values)
+}
+
+// SetFileValues sets the assignments like SetValues, their values are the
+// paths of the files whose content is set, i.e. "config=./config.json"
+func SetFileValues(values map[string]interface{}, assignments string) error {
+	return (&setParser{input: assignments, valueType: fileSetValue}).parse(values)
+}
+
+// setParser parses a comma separated list of assignments
+type setParser struct {
+	input     string
+	pos       int
+	valueType setValueType
+}
+
+func (p *setParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("unable to parse %q at position %d: %s", p.input, p.pos, fmt.Sprintf(format, args...))
+}
+
+// parse sets the assignments in values, only if all of them are valid
+func (p *setParser) parse(values map[string]interface{}) error {
+	return updateValues(values, p.set)
+}
+
+func (p *setParser) set(values map[string]interface{}) error {
+	for p.pos < len(p.input) {
+		segments, err := p.parseKey()
+		if err != nil {
+			return err
+		}
+		value, remove, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		setPath(values, segments, value, remove)
+
+		if p.pos < len(p.input) {
+			if p.input[p.pos] != ',' {
+				return p.errorf("expected ','")
+			}
+			p.pos++
+		}
+	}
+	return nil
+}
+
+// parseKey parses the path of an assignment, up to the "="
+func (p *setParser) parseKey() ([]pathSegment, error) {
+	segments := make([]pathSegment, 0)
+	var key strings.Builder
+	needKey := true     // a key must follow, i.e. at the beginning or after a "."
+	afterIndex := false // the last segment is an index, closed by "]"
+
+	appendKey := func() error {
+		if key.Len() == 0 {
+			if needKey {
+				return p.errorf("empty key")
+			}
+			return nil
+		}
+		segments = append(segments, pathSegment{key: key.String()})
+		key.Reset()
+		needKey = false
+		return nil
+	}
+
+	for {
+		if p.pos >= len(p.input) || p.input[p.pos] == ',' {
+			return nil, p.errorf("missing value")
+		}
+
+		switch c := p.input[p.pos]; c {
+		case '=':
+			err := appendKey()
+			if err != nil {
+				return nil, err
+			}
+			p.pos++
+			return segments, nil
+
+		case '.':
+			err := appendKey()
+			if err != nil {
+				return nil, err
+			}
+			needKey = true
+			afterIndex = false
+			p.pos++
+
+		case '[':
+			if key.Len() == 0 && len(segments) == 0 {
+				return nil, p.errorf("empty key")
+			}
+			err := appendKey()
+			if err != nil {
+				return nil, err
+			}
+			index, err := p.parseIndex()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			afterIndex = true
+
+		default:
+			if afterIndex {
+				return nil, p.errorf("unexpected character %q after an index", c)
+			}
+			if c == '\\' && p.pos+1 < len(p.input) {
+				p.pos++
+				c = p.input[p.pos]
+			}
+			key.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// parseIndex parses a list index, i.e. "[0]"
+func (p *setParser) parseIndex() (int, error) {
+	end := strings.IndexByte(p.input[p.pos:], ']')
+	if end < 0 {
+		return 0, p.errorf("unterminated index")
+	}
+	value := p.input[p.pos+1 : p.pos+end]
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 {
+		return 0, p.errorf("invalid index %q", value)
+	}
+	if index > maxListIndex {
+		return 0, p.errorf("index %d exceeds the maximum of %d", index, maxListIndex)
+	}
+	p.pos += end + 1
+	return index, nil
+}
+
+// parseValue parses the value of an assignment, it returns whether the key
+// must be removed
+func (p *setParser) parseValue() (interface{}, bool, error) {
+	if p.valueType != fileSetValue && p.pos < len(p.input) && p.input[p.pos] == '{' {
+		list, err := p.parseList()
+		return list, false, err
+	}
+
+	raw := p.readUntil(",")
+	switch p.valueType {
+	case stringSetValue:
+		return raw, false, nil
+	case fileSetValue:
+		content, err := readFile(raw)
+		if err != nil {
+			return nil, false, err
+		}
+		return string(content), false, nil
+	}
+	value := typedValue(raw)
+	return value, value == nil, nil
+}
+
+// parseList parses a list of values, i.e. "{a,b}"
+func (p *setParser) parseList() ([]interface{}, error) {
+	p.pos++ // skip the "{"
+	list := make([]interface{}, 0)
+	if p.pos < len(p.input) && p.input[p.pos] == '}' {
+		p.pos++
+		return list, nil
+	}
+
+	for {
+		raw := p.readUntil(",}")
+		if p.valueType == typedSetValue {
+			list = append(list, typedValue(raw))
+		} else {
+			list = append(list, raw)
+		}
+
+		if p.pos >= len(p.input) {
+			return nil, p.errorf("unterminated list")
+		}
+		p.pos++
+		if p.input[p.pos-1] == '}' {
+			return list, nil
+		}
+	}
+}
+
+// readUntil reads the input up to one of the (not escaped) stop characters,
+// resolving the escapes
+func (p *setParser) readUntil(stops string) string {
+	var builder strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c == '\\' && p.pos+1 < len(p.input) {
+			builder.WriteByte(p.input[p.pos+1])
+			p.pos += 2
+			continue
+		}
+		if strings.IndexByte(stops, c) >= 0 {
+			break
+		}
+		builder.WriteByte(c)
+		p.pos++
+	}
+	return builder.String()
+}
+
+// typedValue converts booleans, integers (without leading zeros) and null,
+// that is returned as nil
+func typedValue(raw string) interface{} {
+	switch strings.ToLower(raw) {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if len(raw) > 1 && raw[0] == '0' {
+		return raw
+	}
+	if value, err := strconv.Atoi(raw); err == nil {
+		return value
+	}
+	return raw
+}
+
+// setPath sets the value at the path of the container, creating (or
+// replacing) the maps and lists along it, or removes the key if remove is
+// true; a removal doesn't create nor replace them, and the container is left
+// as it is if the path doesn't exist. It returns the updated container.
+func setPath(container interface{}, segments []pathSegment, value interface{}, remove bool) interface{} {
+	segment := segments[0]
+	last := len(segments) == 1
+
+	if !segment.isIndex {
+		values, ok := container.(map[string]interface{})
+		if !ok {
+			if remove {
+				return container
+			}
+			values = make(map[string]interface{})
+		}
+		switch {
+		case last && remove:
+			delete(values, segment.key)
+		case last:
+			values[segment.key] = value
+		default:
+			next, found := values[segment.key]
+			if !found && remove {
+				return values
+			}
+			values[segment.key] = setPath(next, segments[1:], value, remove)
+		}
+		return values
+	}
+
+	list, ok := container.([]interface{})
+	if remove && (!ok || segment.index >= len(list)) {
+		return container
+	}
+	for len(list) <= segment.index {
+		list = append(list, nil)
+	}
+	if last {
+		list[segment.index] = value
+	} else {
+		list[segment.index] = setPath(list[segment.index], segments[1:], value, remove)
+	}
+	return list
+}
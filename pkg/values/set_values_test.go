@@ -0,0 +1,262 @@
+package values
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      map[string]interface{}
+		assignments string
+		want        map[string]interface{}
+		wantErr     string
+	}{
+		{
+			name:        "Should set a nested key",
+			values:      map[string]interface{}{"image": map[string]interface{}{"repository": "nginx"}},
+			assignments: "image.tag=1.2.3",
+			want:        map[string]interface{}{"image": map[string]interface{}{"repository": "nginx", "tag": "1.2.3"}},
+		},
+		{
+			name:        "Should set multiple comma separated keys",
+			values:      map[string]interface{}{},
+			assignments: "a=x,b.c=y",
+			want:        map[string]interface{}{"a": "x", "b": map[string]interface{}{"c": "y"}},
+		},
+		{
+			name:        "Should convert booleans, integers and keep the other values as strings",
+			values:      map[string]interface{}{},
+			assignments: "a=true,b=FALSE,c=42,d=-3,e=007,f=1.5,g=",
+			want:        map[string]interface{}{"a": true, "b": false, "c": 42, "d": -3, "e": "007", "f": "1.5", "g": ""},
+		},
+		{
+			name:        "Should remove a key set to null",
+			values:      map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": 2}},
+			assignments: "a.b=null",
+			want:        map[string]interface{}{"a": map[string]interface{}{"c": 2}},
+		},
+		{
+			name:        "Should not create the parents of a removed key",
+			values:      map[string]interface{}{"b": 1},
+			assignments: "a.b=null,list[1].c=null",
+			want:        map[string]interface{}{"b": 1},
+		},
+		{
+			name:        "Should not replace the parents of a removed key",
+			values:      map[string]interface{}{"a": "x", "list": []interface{}{"y"}},
+			assignments: "a.b=null,list[0].c=null,list[1]=null",
+			want:        map[string]interface{}{"a": "x", "list": []interface{}{"y"}},
+		},
+		{
+			name:        "Should set an element of an existing list",
+			values:      map[string]interface{}{"list": []interface{}{map[string]interface{}{"name": "a", "port": 80}}},
+			assignments: "list[0].port=8080",
+			want:        map[string]interface{}{"list": []interface{}{map[string]interface{}{"name": "a", "port": 8080}}},
+		},
+		{
+			name:        "Should extend a list with nil elements",
+			values:      map[string]interface{}{},
+			assignments: "list[2]=c",
+			want:        map[string]interface{}{"list": []interface{}{nil, nil, "c"}},
+		},
+		{
+			name:        "Should set nested lists",
+			values:      map[string]interface{}{},
+			assignments: "matrix[1][0]=x",
+			want:        map[string]interface{}{"matrix": []interface{}{nil, []interface{}{"x"}}},
+		},
+		{
+			name:        "Should replace a scalar along the path",
+			values:      map[string]interface{}{"a": "scalar"},
+			assignments: "a.b=x",
+			want:        map[string]interface{}{"a": map[string]interface{}{"b": "x"}},
+		},
+		{
+			name:        "Should set a list literal with typed values",
+			values:      map[string]interface{}{},
+			assignments: "list={a,1,true},empty={},other=x",
+			want:        map[string]interface{}{"list": []interface{}{"a", 1, true}, "empty": []interface{}{}, "other": "x"},
+		},
+		{
+			name:        "Should unescape the keys and the values",
+			values:      map[string]interface{}{},
+			assignments: `annotations.example\.com/name=a\,b\=c,list={x\,y\}}`,
+			want: map[string]interface{}{
+				"annotations": map[string]interface{}{"example.com/name": "a,b=c"},
+				"list":        []interface{}{"x,y}"},
+			},
+		},
+		{
+			name:        "Should keep an equal sign in the value",
+			values:      map[string]interface{}{},
+			assignments: "query=a=b",
+			want:        map[string]interface{}{"query": "a=b"},
+		},
+		{
+			name:        "Should apply the assignments in order",
+			values:      map[string]interface{}{},
+			assignments: "a=x,a=y",
+			want:        map[string]interface{}{"a": "y"},
+		},
+		{
+			name:        "Should ignore an empty string",
+			values:      map[string]interface{}{"a": "x"},
+			assignments: "",
+			want:        map[string]interface{}{"a": "x"},
+		},
+		{
+			name:        "Should return an error if the value is missing",
+			assignments: "a.b",
+			wantErr:     `unable to parse "a.b" at position 3: missing value`,
+		},
+		{
+			name:        "Should return an error if a key is missing before a comma",
+			assignments: "a=1,b,c=2",
+			wantErr:     `unable to parse "a=1,b,c=2" at position 5: missing value`,
+		},
+		{
+			name:        "Should return an error if a key is empty",
+			assignments: "a..b=1",
+			wantErr:     `unable to parse "a..b=1" at position 2: empty key`,
+		},
+		{
+			name:        "Should return an error if the path starts with an index",
+			assignments: "[0]=1",
+			wantErr:     `unable to parse "[0]=1" at position 0: empty key`,
+		},
+		{
+			name:        "Should return an error if the path ends with a dot",
+			assignments: "a.=1",
+			wantErr:     `unable to parse "a.=1" at position 2: empty key`,
+		},
+		{
+			name:        "Should return an error if an index is not a number",
+			assignments: "a[x]=1",
+			wantErr:     `unable to parse "a[x]=1" at position 1: invalid index "x"`,
+		},
+		{
+			name:        "Should return an error if an index is negative",
+			assignments: "a[-1]=1",
+			wantErr:     `unable to parse "a[-1]=1" at position 1: invalid index "-1"`,
+		},
+		{
+			name:        "Should return an error if an index is too big",
+			assignments: "a[65537]=1",
+			wantErr:     `unable to parse "a[65537]=1" at position 1: index 65537 exceeds the maximum of 65536`,
+		},
+		{
+			name:        "Should return an error if an index is not terminated",
+			assignments: "a[0=1",
+			wantErr:     `unable to parse "a[0=1" at position 1: unterminated index`,
+		},
+		{
+			name:        "Should return an error if a key follows an index without a dot",
+			assignments: "a[0]b=1",
+			wantErr:     `unable to parse "a[0]b=1" at position 4: unexpected character 'b' after an index`,
+		},
+		{
+			name:        "Should return an error if a list is not terminated",
+			assignments: "a={x,y",
+			wantErr:     `unable to parse "a={x,y" at position 6: unterminated list`,
+		},
+		{
+			name:        "Should return an error if a list is followed by other characters",
+			assignments: "a={x}y",
+			wantErr:     `unable to parse "a={x}y" at position 5: expected ','`,
+		},
+		{
+			name:        "Should not set any value if an assignment is not valid",
+			values:      map[string]interface{}{"image": map[string]interface{}{"tag": "1.0.0"}},
+			assignments: "image.tag=1.2.3,b=x,list[-1]=y",
+			wantErr:     `unable to parse "image.tag=1.2.3,b=x,list[-1]=y" at position 24: invalid index "-1"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := tt.values
+			if values == nil {
+				values = map[string]interface{}{}
+			}
+			original := copyValue(values)
+
+			err := SetValues(values, tt.assignments)
+
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Equal(t, original, values)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, values)
+		})
+	}
+}
+
+func TestSetStringValues(t *testing.T) {
+	values := map[string]interface{}{}
+
+	err := SetStringValues(values, "a=true,b=42,c=null,list={1,false}")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "true", "b": "42", "c": "null", "list": []interface{}{"1", "false"}}, values)
+}
+
+func TestSetFileValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		assignments string
+		readErr     error
+		want        map[string]interface{}
+		wantErr     string
+	}{
+		{
+			name:        "Should set the content of the files",
+			assignments: `config=some/config.json,script=some\,path.sh`,
+			want: map[string]interface{}{
+				"config": "content of some/config.json",
+				"script": "content of some,path.sh",
+			},
+		},
+		{
+			name:        "Should not parse lists",
+			assignments: "config={a}",
+			want:        map[string]interface{}{"config": "content of {a}"},
+		},
+		{
+			name:        "Should return an error if a file can't be read",
+			assignments: "config=some/config.json",
+			readErr:     errors.New("some-read-error"),
+			wantErr:     "some-read-error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockReadFile(t, tt.readErr)
+			values := map[string]interface{}{}
+
+			err := SetFileValues(values, tt.assignments)
+
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, values)
+		})
+	}
+}
+
+func mockReadFile(t *testing.T, err error) {
+	originalValue := readFile
+	readFile = func(name string) ([]byte, error) {
+		if err != nil {
+			return nil, err
+		}
+		return []byte("content of " + name), nil
+	}
+	t.Cleanup(func() { readFile = originalValue })
+}
@@ -0,0 +1,39 @@
+package values
+
+// updateValues invokes update with a deep copy of values, and replaces the
+// content of values with the updated copy only if update succeeds, so that
+// values is never partially updated
+func updateValues(values map[string]interface{}, update func(map[string]interface{}) error) error {
+	updated := copyValue(values).(map[string]interface{})
+	err := update(updated)
+	if err != nil {
+		return err
+	}
+
+	clear(values)
+	for key, value := range updated {
+		values[key] = value
+	}
+	return nil
+}
+
+// copyValue returns a deep copy of the maps and lists of value, the other
+// values are returned as they are
+func copyValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			out[key] = copyValue(item)
+		}
+		return out
+
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, item := range value {
+			out[i] = copyValue(item)
+		}
+		return out
+	}
+	return value
+}
@@ -0,0 +1,60 @@
+package values
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_updateValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  func(map[string]interface{}) error
+		want    map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "Should apply the updates",
+			update: func(values map[string]interface{}) error {
+				values["a"].(map[string]interface{})["b"] = 2
+				values["list"].([]interface{})[0] = "y"
+				delete(values, "c")
+				return nil
+			},
+			want: map[string]interface{}{
+				"a":    map[string]interface{}{"b": 2},
+				"list": []interface{}{"y"},
+			},
+		},
+		{
+			name: "Should not modify the values if the update fails",
+			update: func(values map[string]interface{}) error {
+				values["a"].(map[string]interface{})["b"] = 2
+				values["list"].([]interface{})[0] = "y"
+				delete(values, "c")
+				return errors.New("some-error")
+			},
+			want: map[string]interface{}{
+				"a":    map[string]interface{}{"b": 1},
+				"list": []interface{}{"x"},
+				"c":    "z",
+			},
+			wantErr: errors.New("some-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]interface{}{
+				"a":    map[string]interface{}{"b": 1},
+				"list": []interface{}{"x"},
+				"c":    "z",
+			}
+
+			err := updateValues(values, tt.update)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, values)
+		})
+	}
+}